@@ -0,0 +1,65 @@
+package apierrors
+
+import "strconv"
+
+// MetadataBizCode 业务错误码在 ErrorInfo.Metadata 中的 key
+//
+//	ErrorInfo 没有业务错误码字段, GRPCStatus 将其放入 metadata 传输, FromError 还原后从 Metadata 中移除
+const MetadataBizCode = "biz_code"
+
+// WithBizCode set business code to current Error
+//
+//	注意不会添加stack
+func (e *Error) WithBizCode(code int32) *Error {
+	err := Clone(e)
+	err.BizCode = code
+	return err
+}
+
+// BizCode returns the business code for a particular error, 0 表示未设置.
+// It supports wrapped errors.
+func BizCode(err error) int32 {
+	if err == nil {
+		return 0
+	}
+	return FromError(err).BizCode
+}
+
+// bizCodeOf 返回 status 的业务错误码, 兼容未实现 GetBizCode 的 IStatus
+func bizCodeOf(status IStatus) int32 {
+	if s, ok := status.(interface{ GetBizCode() int32 }); ok {
+		return s.GetBizCode()
+	}
+	return 0
+}
+
+// withBizCodeMetadata 返回带业务错误码的 metadata 副本, 未设置时原样返回
+func withBizCodeMetadata(md map[string]string, code int32) map[string]string {
+	if code == 0 {
+		return md
+	}
+	ret := make(map[string]string, len(md)+1)
+	for k, v := range md {
+		ret[k] = v
+	}
+	ret[MetadataBizCode] = strconv.Itoa(int(code))
+	return ret
+}
+
+// splitBizCodeMetadata 从 metadata 中取出业务错误码, 返回不含业务错误码的 metadata 副本
+func splitBizCodeMetadata(md map[string]string) (map[string]string, int32) {
+	v, ok := md[MetadataBizCode]
+	if !ok {
+		return md, 0
+	}
+	ret := make(map[string]string, len(md))
+	for k, v := range md {
+		ret[k] = v
+	}
+	delete(ret, MetadataBizCode)
+	code, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return ret, 0
+	}
+	return ret, int32(code)
+}
@@ -0,0 +1,183 @@
+package apierrors
+
+import (
+	status2 "github.com/alkaid/goerrors/apierrors/http/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// RegisterOption RegisterEnum 及 RegisterFileDescriptorSet 的选项
+type RegisterOption func(*registerOptions)
+
+type registerOptions struct {
+	shortReason bool
+}
+
+// WithShortReason 以枚举值名代替全名作为 reason, 对应生成器参数 short_reason=true
+func WithShortReason() RegisterOption {
+	return func(o *registerOptions) {
+		o.shortReason = true
+	}
+}
+
+// RegisterEnum 读取 enum 上的 errors.proto 选项, 注册与生成代码相同的错误, 返回注册的错误
+//
+//	用于无法导入生成代码的场景, 如动态加载上游 FileDescriptorSet 的网关. 未声明错误码的枚举值不会注册.
+//	可在处理请求的同时调用, 与 FromError 等查找并发安全
+func RegisterEnum(enum protoreflect.EnumDescriptor, opts ...RegisterOption) []*Error {
+	o := &registerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	enumOpts := resolveOptions(enum.Options())
+	code := int(proto.GetExtension(enumOpts, E_DefaultCode).(int32))
+	defaultGRPCCode := proto.GetExtension(enumOpts, E_DefaultGrpcCode).(RPCCode)
+	if code == 0 && defaultGRPCCode != RPCCode_OK {
+		code = status2.FromGRPCCode(codes.Code(defaultGRPCCode))
+	}
+	domain := proto.GetExtension(enumOpts, E_Domain).(string)
+	if domain == "" {
+		domain = proto.GetExtension(resolveOptions(enum.ParentFile().Options()), E_DefaultDomain).(string)
+	}
+	var ret []*Error
+	values := enum.Values()
+	for i := 0; i < values.Len(); i++ {
+		if e := newEnumValueError(values.Get(i), code, defaultGRPCCode, domain, o); e != nil {
+			Register(e)
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// newEnumValueError 按与生成器相同的规则构造枚举值对应的错误, 不是错误的枚举值返回 nil
+func newEnumValueError(v protoreflect.EnumValueDescriptor, code int, grpcCode RPCCode, domain string, o *registerOptions) *Error {
+	opts := resolveOptions(v.Options())
+	if c := proto.GetExtension(opts, E_GrpcCode).(RPCCode); c != RPCCode_OK {
+		grpcCode = c
+		code = status2.FromGRPCCode(codes.Code(c))
+	}
+	if c := proto.GetExtension(opts, E_Code).(int32); c != 0 {
+		code = int(c)
+	}
+	if code <= 0 || code > 600 {
+		return nil
+	}
+	reason := string(v.FullName())
+	if o.shortReason {
+		reason = string(v.Name())
+	}
+	message := string(v.Name())
+	if proto.HasExtension(opts, E_Message) {
+		message = proto.GetExtension(opts, E_Message).(string)
+	}
+	e := New(code, reason, message, proto.GetExtension(opts, E_Pretty).(string))
+	e.severity = proto.GetExtension(opts, E_Severity).(Level)
+	e.expected = proto.GetExtension(opts, E_Expected).(bool)
+	e.BizCode = int32(v.Number())
+	if proto.HasExtension(opts, E_BizCode) {
+		e.BizCode = proto.GetExtension(opts, E_BizCode).(int32)
+	}
+	e.Domain = domain
+	e.grpcCode = codes.Code(grpcCode)
+	return e
+}
+
+// resolveOptions 返回可读取 errors.proto 扩展的选项
+//
+//	选项以未知字段保存扩展时(如解析描述符时未使用包含扩展的 resolver), 使用 protoregistry.GlobalTypes 重新解析
+func resolveOptions(opts proto.Message) proto.Message {
+	if opts == nil || len(opts.ProtoReflect().GetUnknown()) == 0 {
+		return opts
+	}
+	b, err := proto.Marshal(opts)
+	if err != nil {
+		return opts
+	}
+	m := opts.ProtoReflect().New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}).Unmarshal(b, m); err != nil {
+		return opts
+	}
+	return m
+}
+
+// RegisterFileDescriptorSet 注册 set 中所有文件(包括嵌套在 message 中)的错误枚举, 返回注册的错误
+//
+//	set 中未包含的依赖从 protoregistry.GlobalFiles 查找, 通常由 protoc --include_imports --descriptor_set_out 生成.
+//	同 RegisterEnum, 可与查找并发调用
+func RegisterFileDescriptorSet(set *descriptorpb.FileDescriptorSet, opts ...RegisterOption) ([]*Error, error) {
+	protos := make(map[string]*descriptorpb.FileDescriptorProto, len(set.GetFile()))
+	for _, f := range set.GetFile() {
+		protos[f.GetName()] = f
+	}
+	r := &fallbackResolver{local: &protoregistry.Files{}}
+	var build func(path string) error
+	build = func(path string) error {
+		if _, err := r.local.FindFileByPath(path); err == nil {
+			return nil
+		}
+		fdp, ok := protos[path]
+		if !ok {
+			// 不在 set 中的依赖由 GlobalFiles 提供
+			return nil
+		}
+		for _, dep := range fdp.GetDependency() {
+			if err := build(dep); err != nil {
+				return err
+			}
+		}
+		fd, err := protodesc.NewFile(fdp, r)
+		if err != nil {
+			return err
+		}
+		return r.local.RegisterFile(fd)
+	}
+	var ret []*Error
+	for _, f := range set.GetFile() {
+		if err := build(f.GetName()); err != nil {
+			return ret, err
+		}
+		fd, err := r.local.FindFileByPath(f.GetName())
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, registerEnums(fd.Enums(), fd.Messages(), opts)...)
+	}
+	return ret, nil
+}
+
+// registerEnums 注册 enums 及 messages 中嵌套的枚举
+func registerEnums(enums protoreflect.EnumDescriptors, messages protoreflect.MessageDescriptors, opts []RegisterOption) []*Error {
+	var ret []*Error
+	for i := 0; i < enums.Len(); i++ {
+		ret = append(ret, RegisterEnum(enums.Get(i), opts...)...)
+	}
+	for i := 0; i < messages.Len(); i++ {
+		m := messages.Get(i)
+		ret = append(ret, registerEnums(m.Enums(), m.Messages(), opts)...)
+	}
+	return ret
+}
+
+// fallbackResolver 优先从 local 查找, 未找到时从 protoregistry.GlobalFiles 查找
+type fallbackResolver struct {
+	local *protoregistry.Files
+}
+
+func (r *fallbackResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.local.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r *fallbackResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.local.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
@@ -0,0 +1,155 @@
+package apierrors
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// descriptorSet 返回包含顶层及嵌套错误枚举的 FileDescriptorSet
+//
+//	经序列化后以不含 errors.proto 扩展的 resolver 解析, 扩展保存为未知字段, 与从文件加载的 set 一致
+func descriptorSet(t *testing.T) *descriptorpb.FileDescriptorSet {
+	t.Helper()
+	fileOpts := &descriptorpb.FileOptions{}
+	proto.SetExtension(fileOpts, E_DefaultDomain, "user.example.com")
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, E_DefaultCode, int32(500))
+	notFound := &descriptorpb.EnumValueOptions{}
+	proto.SetExtension(notFound, E_Code, int32(404))
+	proto.SetExtension(notFound, E_Message, "user not found")
+	proto.SetExtension(notFound, E_Pretty, "用户不存在")
+	proto.SetExtension(notFound, E_BizCode, int32(40401))
+	proto.SetExtension(notFound, E_Severity, Level_INFO)
+	proto.SetExtension(notFound, E_Expected, true)
+	timeout := &descriptorpb.EnumValueOptions{}
+	proto.SetExtension(timeout, E_GrpcCode, RPCCode_DEADLINE_EXCEEDED)
+	nestedOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(nestedOpts, E_DefaultGrpcCode, RPCCode_DEADLINE_EXCEEDED)
+	proto.SetExtension(nestedOpts, E_Domain, "order.example.com")
+	value := func(name string, number int32, opts *descriptorpb.EnumValueOptions) *descriptorpb.EnumValueDescriptorProto {
+		return &descriptorpb.EnumValueDescriptorProto{Name: proto.String(name), Number: proto.Int32(number), Options: opts}
+	}
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("desc/v1/errors.proto"),
+		Package: proto.String("desc.v1"),
+		Syntax:  proto.String("proto3"),
+		Options: fileOpts,
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{
+				Name:    proto.String("ErrorReason"),
+				Options: enumOpts,
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					value("UNSPECIFIED", 0, nil),
+					value("USER_NOT_FOUND", 1, notFound),
+					value("TIMEOUT", 2, timeout),
+				},
+			},
+			// 未声明错误码的枚举不注册
+			{
+				Name:  proto.String("Status"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{value("STATUS_UNSPECIFIED", 0, nil)},
+			},
+		},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name:    proto.String("Error"),
+				Options: nestedOpts,
+				Value:   []*descriptorpb.EnumValueDescriptorProto{value("ORDER_UNSPECIFIED", 0, nil), value("ORDER_EXPIRED", 3, nil)},
+			}},
+		}},
+	}}}
+	b, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := &descriptorpb.FileDescriptorSet{}
+	if err := (proto.UnmarshalOptions{Resolver: &protoregistry.Types{}}).Unmarshal(b, loaded); err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+func TestRegisterFileDescriptorSet(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []RegisterOption
+		// want 期望注册的 reason 及对应的错误, 枚举值的全名与枚举同级
+		want map[string]*Error
+	}{
+		{
+			name: "full reason",
+			want: map[string]*Error{
+				"desc.v1.UNSPECIFIED":             New(500, "", "UNSPECIFIED", "").WithDomain("user.example.com"),
+				"desc.v1.USER_NOT_FOUND":          New(404, "", "user not found", "用户不存在").WithDomain("user.example.com").WithBizCode(40401),
+				"desc.v1.TIMEOUT":                 New(504, "", "TIMEOUT", "").WithDomain("user.example.com").WithBizCode(2),
+				"desc.v1.Order.ORDER_UNSPECIFIED": New(504, "", "ORDER_UNSPECIFIED", "").WithDomain("order.example.com"),
+				"desc.v1.Order.ORDER_EXPIRED":     New(504, "", "ORDER_EXPIRED", "").WithDomain("order.example.com").WithBizCode(3),
+			},
+		},
+		{
+			name: "short reason",
+			opts: []RegisterOption{WithShortReason()},
+			want: map[string]*Error{
+				"UNSPECIFIED":       New(500, "", "UNSPECIFIED", "").WithDomain("user.example.com"),
+				"USER_NOT_FOUND":    New(404, "", "user not found", "用户不存在").WithDomain("user.example.com").WithBizCode(40401),
+				"TIMEOUT":           New(504, "", "TIMEOUT", "").WithDomain("user.example.com").WithBizCode(2),
+				"ORDER_UNSPECIFIED": New(504, "", "ORDER_UNSPECIFIED", "").WithDomain("order.example.com"),
+				"ORDER_EXPIRED":     New(504, "", "ORDER_EXPIRED", "").WithDomain("order.example.com").WithBizCode(3),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRegistry(t)
+			ret, err := RegisterFileDescriptorSet(descriptorSet(t), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ret) != len(tt.want) {
+				t.Errorf("registered %d errors, want %d", len(ret), len(tt.want))
+			}
+			for reason, want := range tt.want {
+				e, ok := LookupDomain(want.Domain, reason)
+				if !ok {
+					t.Errorf("%s is not registered", reason)
+					continue
+				}
+				if e.Code != want.Code || e.Message != want.Message || e.Pretty != want.Pretty ||
+					e.BizCode != want.BizCode || e.Domain != want.Domain {
+					t.Errorf("%s = %+v, want %+v", reason, &e.Status, &want.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterEnum(t *testing.T) {
+	resetRegistry(t)
+	fd, err := protodesc.NewFile(descriptorSet(t).GetFile()[0], protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := RegisterEnum(fd.Enums().ByName("ErrorReason"))
+	if len(ret) != 3 {
+		t.Fatalf("registered %d errors, want 3", len(ret))
+	}
+	e, ok := Lookup("desc.v1.USER_NOT_FOUND")
+	if !ok {
+		t.Fatal("USER_NOT_FOUND is not registered")
+	}
+	if e.Severity() != Level_INFO || !e.Expected() || e.GRPCCode() != codes.NotFound {
+		t.Errorf("severity, expected, grpc code = %v, %v, %v, want INFO, true, NotFound", e.Severity(), e.Expected(), e.GRPCCode())
+	}
+	if e, _ := Lookup("desc.v1.TIMEOUT"); e.GRPCCode() != codes.DeadlineExceeded {
+		t.Errorf("TIMEOUT grpc code = %v, want DeadlineExceeded", e.GRPCCode())
+	}
+	if ret := RegisterEnum(fd.Enums().ByName("Status")); len(ret) != 0 {
+		t.Errorf("enum without codes registered %d errors, want none", len(ret))
+	}
+}
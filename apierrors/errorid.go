@@ -0,0 +1,77 @@
+package apierrors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MetadataErrorID 错误ID在 Metadata 中的 key
+//
+//	错误ID随 Metadata 一起经 ErrorInfo 传输,客户端看到的错误与服务端日志可通过该ID关联
+const MetadataErrorID = "error_id"
+
+// IDGenerator 错误ID生成器
+type IDGenerator func(ctx context.Context) string
+
+// DefaultIDGenerator 默认错误ID生成器
+//
+//	ctx 中存在有效的 trace 时使用 trace ID,否则生成随机ID
+var DefaultIDGenerator IDGenerator = TraceOrRandomID
+
+// TraceOrRandomID 优先返回 ctx 中的 trace ID,不存在时返回 RandomID
+func TraceOrRandomID(ctx context.Context) string {
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			return sc.TraceID().String()
+		}
+	}
+	return RandomID(ctx)
+}
+
+// RandomID 返回 16 字节随机数的 hex 编码
+func RandomID(_ context.Context) string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// WithErrorID set error id to current Error
+//
+//	注意不会添加stack
+func (e *Error) WithErrorID(id string) *Error {
+	return e.WithMetadata(map[string]string{MetadataErrorID: id})
+}
+
+// ErrorID returns the error id of current Error.
+func (e *Error) ErrorID() string {
+	return e.Metadata[MetadataErrorID]
+}
+
+// ErrorID returns the error id for a particular error.
+// It supports wrapped errors.
+func ErrorID(err error) string {
+	if err == nil {
+		return ""
+	}
+	return FromError(err).ErrorID()
+}
+
+// StampErrorID 为 err 打上错误ID
+//
+//	已有错误ID时保留原ID,否则使用 DefaultIDGenerator 生成.
+//	通常由服务端在错误离开服务前调用,例如 grpc 拦截器或 http 错误输出
+func StampErrorID(ctx context.Context, err error) *Error {
+	if err == nil {
+		return nil
+	}
+	se := FromError(err)
+	if se.ErrorID() != "" {
+		return se
+	}
+	return se.WithErrorID(DefaultIDGenerator(ctx))
+}
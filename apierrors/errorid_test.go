@@ -0,0 +1,66 @@
+package apierrors
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// fixedID 将 DefaultIDGenerator 替换为返回 id 的生成器, 测试结束后恢复
+func fixedID(t *testing.T, id string) {
+	t.Helper()
+	old := DefaultIDGenerator
+	DefaultIDGenerator = func(ctx context.Context) string { return id }
+	t.Cleanup(func() { DefaultIDGenerator = old })
+}
+
+func TestStampErrorID(t *testing.T) {
+	fixedID(t, "generated")
+	sentinel := New(404, "id.v1.NOT_FOUND", "not found", "")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "absent", err: sentinel, want: "generated"},
+		{name: "present", err: sentinel.WithErrorID("upstream"), want: "upstream"},
+		{name: "plain error", err: context.Canceled, want: "generated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StampErrorID(context.Background(), tt.err).ErrorID(); got != tt.want {
+				t.Errorf("ErrorID = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if sentinel.ErrorID() != "" {
+		t.Errorf("StampErrorID modified the original error")
+	}
+	if StampErrorID(context.Background(), nil) != nil {
+		t.Errorf("StampErrorID(nil) should be nil")
+	}
+}
+
+func TestErrorIDRoundTrip(t *testing.T) {
+	fixedID(t, "generated")
+	e := StampErrorID(context.Background(), New(500, "id.v1.INTERNAL", "internal", "").WithErrorID("upstream"))
+	t.Run("grpc", func(t *testing.T) {
+		if got := ErrorID(FromError(e.GRPCStatus().Err())); got != "upstream" {
+			t.Errorf("ErrorID = %q, want upstream", got)
+		}
+	})
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := &Error{}
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatal(err)
+		}
+		// 再次经过服务端时保留原ID
+		if id := StampErrorID(context.Background(), got).ErrorID(); id != "upstream" {
+			t.Errorf("ErrorID = %q, want upstream", id)
+		}
+	})
+}
@@ -4,11 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 
 	status2 "github.com/alkaid/goerrors/apierrors/http/status"
 
 	pkgerrors "github.com/alkaid/goerrors/errors"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -27,19 +30,126 @@ const (
 	SupportPackageIsVersion1 = true
 )
 
-type errKey string
+type errKey struct {
+	domain string
+	reason string
+}
+
+// errsMu 保护 errs、reasonErrs 及 shortErrs, 注册可与查找并发进行(如网关运行时注册上游错误)
+var errsMu sync.RWMutex
 
 var errs = map[errKey]*Error{}
 
+// reasonErrs 按 reason 索引的错误,用于不带 domain 的查找
+var reasonErrs = map[string][]*Error{}
+
+// shortErrs 按 reason 最后一段(即枚举值名)索引的错误,仅用于识别 kratos 等使用枚举值名作为 reason 的错误
+var shortErrs = map[string][]*Error{}
+
 // Register 注册错误信息
+//
+//	以 (domain, reason) 为key, 重复注册时覆盖. 可与 Lookup、FromError 等并发调用
 func Register(e *Error) {
-	errs[errKey(e.Reason)] = e
+	errsMu.Lock()
+	defer errsMu.Unlock()
+	key := errKey{domain: e.Domain, reason: e.Reason}
+	old := errs[key]
+	errs[key] = e
+	index := func(m map[string][]*Error, reason string) {
+		list := m[reason]
+		for i, re := range list {
+			if re == old {
+				list[i] = e
+				return
+			}
+		}
+		m[reason] = append(list, e)
+	}
+	index(reasonErrs, e.Reason)
+	if short := shortReason(e.Reason); short != e.Reason {
+		index(shortErrs, short)
+	}
+}
+
+// Lookup 按 reason 查找已注册的错误
+func Lookup(reason string) (*Error, bool) {
+	return LookupDomain("", reason)
+}
+
+// LookupDomain 按 (domain, reason) 查找已注册的错误
+//
+//	未精确匹配时在 domain 为空(任一方未声明 domain)的同 reason 错误中查找,仅在唯一匹配时返回.
+//	domain 非空而匹配到的错误未声明 domain 时, 返回带 domain 的副本, 不会丢失传入的 domain
+func LookupDomain(domain, reason string) (*Error, bool) {
+	errsMu.RLock()
+	if e, ok := errs[errKey{domain: domain, reason: reason}]; ok {
+		errsMu.RUnlock()
+		return e, true
+	}
+	var loose []*Error
+	for _, e := range reasonErrs[reason] {
+		if domain == "" || e.Domain == "" {
+			loose = append(loose, e)
+		}
+	}
+	errsMu.RUnlock()
+	if len(loose) != 1 {
+		return nil, false
+	}
+	if e := loose[0]; domain != "" && e.Domain != domain {
+		return e.WithDomain(domain), true
+	}
+	return loose[0], true
+}
+
+// lookupShort 按枚举值名(如 USER_NOT_FOUND)查找已注册的错误, 仅在唯一匹配时返回
+func lookupShort(name string) (*Error, bool) {
+	errsMu.RLock()
+	defer errsMu.RUnlock()
+	if list := shortErrs[name]; len(list) == 1 {
+		return list[0], true
+	}
+	return nil, false
+}
+
+// shortReason 返回 reason 的最后一段
+func shortReason(reason string) string {
+	return reason[strings.LastIndex(reason, ".")+1:]
+}
+
+// ErrorConverter 将第三方错误转换为 *Error, 无法识别时返回 false
+type ErrorConverter func(err error) (*Error, bool)
+
+// convertersMu 保护 converters, 注册可与 FromError 并发进行
+var convertersMu sync.RWMutex
+
+var converters = []ErrorConverter{fromKratos}
+
+// RegisterConverter 注册 FromError 使用的第三方错误转换器,通常在 init 中调用
+//
+//	按注册顺序尝试,优先于 grpc status 的转换. 可与 FromError 并发调用
+func RegisterConverter(c ErrorConverter) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters = append(converters, c)
+}
+
+// registeredConverters 返回已注册的转换器
+//
+//	converters 只追加, 返回的切片不会被修改, 调用转换器时无需持有锁
+func registeredConverters() []ErrorConverter {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	return converters
 }
 
 // Error is a status error.
 type Error struct {
 	Status
-	cause error
+	cause    error
+	severity Level
+	expected bool
+	grpcCode codes.Code
 }
 
 func (e *Error) Error() string {
@@ -57,9 +167,12 @@ func (e *Error) Unwrap() error { return e.cause }
 func (e *Error) Cause() error  { return e.cause }
 
 // Is matches each error in the chain with the target value.
+//
+//	domain 任一方为空时不比较, LookupDomain 回退到未声明 domain 的错误时还原的错误仍匹配该错误
 func (e *Error) Is(err error) bool {
 	if se := new(Error); errors.As(err, &se) {
-		return se.Code == e.Code && se.Reason == e.Reason
+		return se.Code == e.Code && se.Reason == e.Reason &&
+			(se.Domain == e.Domain || se.Domain == "" || e.Domain == "")
 	}
 	return false
 }
@@ -80,6 +193,13 @@ func (e *Error) WithStack() error {
 	return pkgerrors.WithStack(e)
 }
 
+// WithStackSkip 同 WithStack, 但堆栈从调用者向上跳过 skip 层开始记录
+//
+//	用于封装了 WithStack 的辅助函数(如生成的 NewXxx 构造函数), 使堆栈从辅助函数的调用处开始
+func (e *Error) WithStackSkip(skip int) error {
+	return pkgerrors.WithStackSkip(e, skip+1)
+}
+
 // WithMessage set message to current Error
 //
 //	注意不会添加stack
@@ -116,6 +236,24 @@ func (e *Error) WithPretty(pretty string) *Error {
 	return err
 }
 
+// WithDomain set domain to current Error
+//
+//	domain 为错误所属服务的全局唯一名称, 如 user.api.example.com, 同一 domain 内 reason 唯一, 见 Google AIP-193.
+//	注意不会添加stack
+func (e *Error) WithDomain(domain string) *Error {
+	err := Clone(e)
+	err.Domain = domain
+	return err
+}
+
+// domainOf 返回 status 的 domain, 兼容未实现 GetDomain 的 IStatus
+func domainOf(status IStatus) string {
+	if s, ok := status.(interface{ GetDomain() string }); ok {
+		return s.GetDomain()
+	}
+	return ""
+}
+
 // WithMetadata with an MD formed by the mapping of key, value.
 //
 //	注意不会添加stack
@@ -129,10 +267,11 @@ func (e *Error) WithMetadata(md map[string]string) *Error {
 
 // GRPCStatus returns the Status represented by se.
 func (e *Error) GRPCStatus() *status.Status {
-	s, _ := status.New(status2.ToGRPCCode(int(e.Code)), e.Message).
+	s, _ := status.New(e.GRPCCode(), e.Message).
 		WithDetails(&errdetails.ErrorInfo{
 			Reason:   e.Reason,
-			Metadata: e.Metadata,
+			Domain:   e.Domain,
+			Metadata: withBizCodeMetadata(e.Metadata, e.BizCode),
 		})
 	return s
 }
@@ -185,20 +324,28 @@ func Reason(err error) string {
 
 // Clone deep clone error to a new error.
 func Clone(err *Error) *Error {
-	metadata := make(map[string]string, len(err.Metadata))
-	for k, v := range err.Metadata {
+	e := &Error{}
+	e.assign(err)
+	return e
+}
+
+// assign deep copies src to e.
+func (e *Error) assign(src *Error) {
+	metadata := make(map[string]string, len(src.Metadata))
+	for k, v := range src.Metadata {
 		metadata[k] = v
 	}
-	return &Error{
-		cause: err.cause,
-		Status: Status{
-			Code:     err.Code,
-			Reason:   err.Reason,
-			Message:  err.Message,
-			Metadata: metadata,
-			Pretty:   err.Pretty,
-		},
-	}
+	e.cause = src.cause
+	e.severity = src.severity
+	e.expected = src.expected
+	e.grpcCode = src.grpcCode
+	e.Code = src.Code
+	e.Reason = src.Reason
+	e.Message = src.Message
+	e.Metadata = metadata
+	e.Pretty = src.Pretty
+	e.BizCode = src.BizCode
+	e.Domain = src.Domain
 }
 
 // FromError try to convert an error to *Error.
@@ -213,6 +360,11 @@ func FromError(err error) *Error {
 	if se := new(Error); errors.As(err, &se) {
 		return se
 	}
+	for _, c := range registeredConverters() {
+		if se, ok := c(err); ok {
+			return se
+		}
+	}
 	gs, ok := status.FromError(err)
 	if ok {
 		ret := New(
@@ -224,20 +376,27 @@ func FromError(err error) *Error {
 		for _, detail := range gs.Details() {
 			switch d := detail.(type) {
 			case *errdetails.ErrorInfo:
-				e, ok := errs[errKey(d.Reason)]
-				if ok {
-					return e.WithMessage(gs.Message()).WithMetadata(d.Metadata)
+				md, bizCode := splitBizCodeMetadata(d.Metadata)
+				if e, ok := LookupDomain(d.Domain, d.Reason); ok {
+					ret = e.WithMessage(gs.Message()).WithMetadata(md)
+				} else {
+					ret = New(
+						status2.FromGRPCCode(gs.Code()),
+						d.Reason,
+						gs.Message(),
+						"",
+					).WithDomain(d.Domain).WithMetadata(md).WithCause(err)
 				}
-				return New(
-					status2.FromGRPCCode(gs.Code()),
-					d.Reason,
-					gs.Message(),
-					"",
-				).WithMetadata(d.Metadata).WithCause(err)
+				if bizCode != 0 {
+					ret.BizCode = bizCode
+				}
+				ret.grpcCode = gs.Code()
+				return ret
 			default:
 				// do nothing
 			}
 		}
+		ret.grpcCode = gs.Code()
 		return ret
 	}
 	return New(UnknownCode, UnknownReason, err.Error(), "").WithCause(err)
@@ -258,10 +417,22 @@ func FromStatus(status IStatus) error {
 		Message:  status.GetMessage(),
 		Metadata: metadata,
 		Pretty:   status.GetPretty(),
+		BizCode:  bizCodeOf(status),
+		Domain:   domainOf(status),
 	}}
 	return e.WithStack()
 }
 
+// FromStatusRegistered 将 IStatus 转为 Error, 不带 stack
+//
+//	reason 已注册时返回注册的错误并覆盖 message 及 metadata, 用于从各种传输格式还原错误
+func FromStatusRegistered(status IStatus) *Error {
+	if e, ok := LookupDomain(domainOf(status), status.GetReason()); ok {
+		return e.WithMessage(status.GetMessage()).WithMetadata(status.GetMetadata())
+	}
+	return FromStatusWithoutStack(status)
+}
+
 // FromStatusWithoutStack 将 IStatus 转为 Error, 不带 stack
 //
 //	@param status
@@ -277,5 +448,7 @@ func FromStatusWithoutStack(status IStatus) *Error {
 		Message:  status.GetMessage(),
 		Metadata: metadata,
 		Pretty:   status.GetPretty(),
+		BizCode:  bizCodeOf(status),
+		Domain:   domainOf(status),
 	}}
 }
@@ -0,0 +1,201 @@
+package apierrors
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLookupDomain(t *testing.T) {
+	userNotFound := New(404, "user.v1.NOT_FOUND", "user not found", "").WithDomain("user.example.com")
+	orderNotFound := New(404, "order.v1.NOT_FOUND", "order not found", "").WithDomain("order.example.com")
+	plain := New(400, "plain.v1.INVALID", "invalid", "")
+	tests := []struct {
+		name     string
+		register []*Error
+		domain   string
+		reason   string
+		want     *Error
+		// wantDomain 返回错误的 domain, 为空时与 want 相同
+		wantDomain string
+	}{
+		{
+			name:     "exact match",
+			register: []*Error{userNotFound, orderNotFound},
+			domain:   "user.example.com",
+			reason:   "user.v1.NOT_FOUND",
+			want:     userNotFound,
+		},
+		{
+			name:     "exact match without domain",
+			register: []*Error{plain},
+			reason:   "plain.v1.INVALID",
+			want:     plain,
+		},
+		{
+			name:     "short reason is not matched",
+			register: []*Error{userNotFound, orderNotFound},
+			domain:   "order.example.com",
+			reason:   "NOT_FOUND",
+		},
+		{
+			name:     "full reason in wrong domain",
+			register: []*Error{userNotFound},
+			domain:   "order.example.com",
+			reason:   "user.v1.NOT_FOUND",
+		},
+		{
+			name:     "loose fallback without incoming domain",
+			register: []*Error{userNotFound},
+			reason:   "user.v1.NOT_FOUND",
+			want:     userNotFound,
+		},
+		{
+			name:       "loose fallback keeps incoming domain",
+			register:   []*Error{plain},
+			domain:     "billing.example.com",
+			reason:     "plain.v1.INVALID",
+			want:       plain,
+			wantDomain: "billing.example.com",
+		},
+		{
+			name:     "ambiguous reason without domain",
+			register: []*Error{userNotFound, userNotFound.WithDomain("user2.example.com")},
+			reason:   "user.v1.NOT_FOUND",
+		},
+		{
+			name:     "unknown reason",
+			register: []*Error{userNotFound},
+			reason:   "UNKNOWN",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRegistry(t)
+			for _, e := range tt.register {
+				Register(e)
+			}
+			got, ok := LookupDomain(tt.domain, tt.reason)
+			if tt.want == nil {
+				if ok {
+					t.Fatalf("LookupDomain(%q, %q) = %v, want not found", tt.domain, tt.reason, got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("LookupDomain(%q, %q) not found", tt.domain, tt.reason)
+			}
+			wantDomain := tt.wantDomain
+			if wantDomain == "" {
+				wantDomain = tt.want.Domain
+			}
+			if got.Reason != tt.want.Reason || got.Code != tt.want.Code || got.Domain != wantDomain {
+				t.Errorf("LookupDomain(%q, %q) = %v (domain %q), want %v (domain %q)", tt.domain, tt.reason, got, got.Domain, tt.want, wantDomain)
+			}
+		})
+	}
+}
+
+func TestRegisterReplaces(t *testing.T) {
+	resetRegistry(t)
+	Register(New(404, "user.v1.NOT_FOUND", "old", ""))
+	Register(New(404, "user.v1.NOT_FOUND", "new", ""))
+	if e, ok := Lookup("user.v1.NOT_FOUND"); !ok || e.Message != "new" {
+		t.Errorf("Lookup = %v, %v, want replaced entry", e, ok)
+	}
+	if e, ok := lookupShort("NOT_FOUND"); !ok || e.Message != "new" {
+		t.Errorf("lookupShort = %v, %v, want replaced entry", e, ok)
+	}
+}
+
+func TestFromErrorKeepsRemoteDomain(t *testing.T) {
+	resetRegistry(t)
+	local := New(404, "NOT_FOUND", "not found", "")
+	Register(local)
+	s, _ := status.New(codes.NotFound, "invoice not found").WithDetails(&errdetails.ErrorInfo{
+		Reason: "NOT_FOUND",
+		Domain: "billing.example.com",
+	})
+	e := FromError(s.Err())
+	if e.Reason != "NOT_FOUND" || e.Domain != "billing.example.com" || e.Message != "invoice not found" {
+		t.Errorf("FromError = %v (domain %q), want remote domain kept", e, e.Domain)
+	}
+	if !errors.Is(e, local) {
+		t.Errorf("remote error rehydrated from the local error without domain should match it")
+	}
+	if other := New(404, "NOT_FOUND", "", "").WithDomain("user.example.com"); errors.Is(e, other) {
+		t.Errorf("remote error should not match an error in another domain")
+	}
+}
+
+func TestRegisterConcurrent(t *testing.T) {
+	resetRegistry(t)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			Register(New(404, "user.v1.NOT_FOUND", "user not found", "").WithDomain("user.example.com"))
+			Register(New(400, "order.v1.INVALID", "invalid", ""))
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		LookupDomain("user.example.com", "NOT_FOUND")
+		Lookup("order.v1.INVALID")
+	}
+	<-done
+}
+
+func TestRegisterConverterConcurrent(t *testing.T) {
+	old := converters
+	t.Cleanup(func() { converters = old })
+	errConverted := errors.New("converted")
+	converted := New(418, "convert.v1.TEAPOT", "", "")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			RegisterConverter(func(err error) (*Error, bool) { return nil, false })
+		}
+		RegisterConverter(func(err error) (*Error, bool) { return converted, err == errConverted }) //nolint:errorlint,goerr113
+	}()
+	for i := 0; i < 1000; i++ {
+		FromError(errors.New("plain"))
+	}
+	<-done
+	if e := FromError(errConverted); e != converted {
+		t.Errorf("FromError = %v, want the error from the converter registered last", e)
+	}
+}
+
+var stackNotFound = New(404, "stack.v1.NOT_FOUND", "not found", "")
+
+// newStackNotFound 与 protoc-gen-go-errors 以 constructors=true 生成的 NewXxx 相同
+func newStackNotFound(cause error, format string, args ...any) error {
+	err := stackNotFound.WithCause(cause)
+	if format != "" {
+		err = err.WithMessage(err.Message + ". " + fmt.Sprintf(format, args...))
+	}
+	return err.WithStackSkip(1)
+}
+
+func TestConstructorStack(t *testing.T) {
+	err := newStackNotFound(errors.New("io"), "id=%d", 1)
+	var st interface{ StackTrace() pkgerrors.StackTrace }
+	if !errors.As(err, &st) || len(st.StackTrace()) == 0 {
+		t.Fatalf("%v has no stack trace", err)
+	}
+	// 堆栈最内层为 NewXxx 的调用者而非 NewXxx 本身
+	if top := runtime.FuncForPC(uintptr(st.StackTrace()[0]) - 1).Name(); !strings.HasSuffix(top, ".TestConstructorStack") {
+		t.Errorf("top frame = %s, want the caller of newStackNotFound", top)
+	}
+	if e := FromError(err); e.Message != "not found. id=1" || !errors.Is(err, stackNotFound) {
+		t.Errorf("FromError = %v, want the registered error with the formatted message", e)
+	}
+}
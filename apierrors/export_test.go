@@ -0,0 +1,4 @@
+package apierrors
+
+// KratosErrorsPath 供外部测试以本测试包代替 kratos 错误包
+var KratosErrorsPath = &kratosErrorsPath
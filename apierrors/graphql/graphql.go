@@ -0,0 +1,200 @@
+// Package graphql
+//
+//	apierrors 与 GraphQL 错误之间的转换, Presenter 可直接用作 gqlgen 的 ErrorPresenter
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// ExtensionCode 错误码的扩展字段
+	ExtensionCode = "code"
+	// ExtensionReason 错误原因的扩展字段
+	ExtensionReason = "reason"
+	// ExtensionPretty 供用户阅读的错误信息的扩展字段
+	ExtensionPretty = "pretty"
+	// ExtensionMetadata metadata 的扩展字段
+	ExtensionMetadata = "metadata"
+)
+
+// Option Presenter 选项
+type Option func(*options)
+
+type options struct {
+	metadataKeys map[string]bool
+}
+
+// WithMetadataKeys 设置允许输出到 extensions.metadata 的 metadata key, 默认仅输出错误ID
+func WithMetadataKeys(keys ...string) Option {
+	return func(o *options) {
+		for _, k := range keys {
+			o.metadataKeys[k] = true
+		}
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{metadataKeys: map[string]bool{apierrors.MetadataErrorID: true}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Presenter 返回将 err 转换为 GraphQL 错误的函数, 与 gqlgen 的 graphql.ErrorPresenterFunc 兼容
+//
+//	resolver 的错误经 gqlgen 包装为带 path 的 *gqlerror.Error 时保留 path 及 locations;
+//	不含底层错误的 *gqlerror.Error(如查询校验错误)原样返回
+func Presenter(opts ...Option) func(ctx context.Context, err error) *gqlerror.Error {
+	o := newOptions(opts)
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		return o.present(err)
+	}
+}
+
+// PresentList 将 err 转换为 GraphQL 错误列表
+//
+//	gqlerror.List 及实现了 Unwrap() []error 的聚合错误会被展开, 每个错误单独转换
+func PresentList(err error, opts ...Option) gqlerror.List {
+	o := newOptions(opts)
+	var list gqlerror.List
+	for _, e := range flatten(err) {
+		list = append(list, o.present(e))
+	}
+	return list
+}
+
+func flatten(err error) []error {
+	if err == nil {
+		return nil
+	}
+	var list gqlerror.List
+	if errors.As(err, &list) {
+		var errs []error
+		for _, e := range list {
+			if e != nil {
+				errs = append(errs, flatten(e)...)
+			}
+		}
+		return errs
+	}
+	if multi, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint // 聚合错误只在最外层展开
+		var errs []error
+		for _, e := range multi.Unwrap() {
+			errs = append(errs, flatten(e)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
+
+func (o *options) present(err error) *gqlerror.Error {
+	if err == nil {
+		return nil
+	}
+	if ge, ok := err.(*gqlerror.Error); ok { //nolint:errorlint // 仅处理 gqlgen 直接传入的 *gqlerror.Error
+		inner := errors.Unwrap(ge)
+		if inner == nil {
+			return ge
+		}
+		ret := o.convert(inner)
+		ret.Path = ge.Path
+		ret.Locations = ge.Locations
+		for k, v := range ge.Extensions {
+			if _, ok := ret.Extensions[k]; !ok {
+				ret.Extensions[k] = v
+			}
+		}
+		return ret
+	}
+	return o.convert(err)
+}
+
+func (o *options) convert(err error) *gqlerror.Error {
+	e := apierrors.FromError(err)
+	ext := map[string]any{
+		ExtensionCode:   e.Code,
+		ExtensionReason: e.Reason,
+	}
+	if e.Pretty != "" {
+		ext[ExtensionPretty] = e.Pretty
+	}
+	md := map[string]string{}
+	for k, v := range e.Metadata {
+		if o.metadataKeys[k] {
+			md[k] = v
+		}
+	}
+	if len(md) > 0 {
+		ext[ExtensionMetadata] = md
+	}
+	return &gqlerror.Error{
+		Message:    e.Message,
+		Extensions: ext,
+	}
+}
+
+// Decode 将 GraphQL 错误转换为 *apierrors.Error
+//
+//	reason 已注册时返回注册的错误并覆盖 message 及 metadata; ge 为 nil 时返回 nil
+func Decode(ge *gqlerror.Error) *apierrors.Error {
+	if ge == nil {
+		return nil
+	}
+	reason, _ := ge.Extensions[ExtensionReason].(string)
+	pretty, _ := ge.Extensions[ExtensionPretty].(string)
+	md := map[string]string{}
+	switch m := ge.Extensions[ExtensionMetadata].(type) {
+	case map[string]string:
+		for k, v := range m {
+			md[k] = v
+		}
+	case map[string]any:
+		for k, v := range m {
+			if s, ok := v.(string); ok {
+				md[k] = s
+			}
+		}
+	}
+	if e, ok := apierrors.Lookup(reason); ok {
+		return e.WithMessage(ge.Message).WithMetadata(md)
+	}
+	code := apierrors.UnknownCode
+	switch c := ge.Extensions[ExtensionCode].(type) {
+	case float64:
+		code = int(c)
+	case int32:
+		code = int(c)
+	case int:
+		code = c
+	}
+	return apierrors.New(code, reason, ge.Message, pretty).WithMetadata(md)
+}
+
+// DecodeList 将 GraphQL 错误列表转换为 *apierrors.Error 列表, 跳过列表中的 nil(如 errors 数组中的 null)
+func DecodeList(list gqlerror.List) []*apierrors.Error {
+	errs := make([]*apierrors.Error, 0, len(list))
+	for _, ge := range list {
+		if ge != nil {
+			errs = append(errs, Decode(ge))
+		}
+	}
+	return errs
+}
+
+// DecodeResponse 从 GraphQL 响应 body 的 errors 数组中还原 *apierrors.Error 列表
+func DecodeResponse(body []byte) ([]*apierrors.Error, error) {
+	var resp struct {
+		Errors gqlerror.List `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return DecodeList(resp.Errors), nil
+}
@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	registered := apierrors.New(404, "gql.v1.GQL_NOT_FOUND", "not found", "no such item")
+	apierrors.Register(registered)
+	present := Presenter()
+	list := gqlerror.List{
+		present(context.Background(), registered.WithMessage("item 7 not found")),
+		present(context.Background(), apierrors.New(409, "gql.v1.CONFLICT", "conflict", "try again")),
+	}
+	body, err := json.Marshal(map[string]any{"errors": list})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, err := DecodeResponse(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2", len(errs))
+	}
+	if !errors.Is(errs[0], registered) || errs[0].Message != "item 7 not found" {
+		t.Errorf("errs[0] = %v, want registered error", errs[0])
+	}
+	if e := errs[1]; e.Code != 409 || e.Reason != "gql.v1.CONFLICT" || e.Pretty != "try again" {
+		t.Errorf("errs[1] = %v", e)
+	}
+}
+
+func TestDecodeResponseNullEntries(t *testing.T) {
+	errs, err := DecodeResponse([]byte(`{"errors":[null,{"message":"boom","extensions":{"code":503}},null]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Code != 503 || errs[0].Message != "boom" {
+		t.Errorf("DecodeResponse = %v, want one error", errs)
+	}
+	if Decode(nil) != nil {
+		t.Errorf("Decode(nil) should be nil")
+	}
+	if list := PresentList(gqlerror.List{nil, gqlerror.Errorf("bad query")}); len(list) != 1 {
+		t.Errorf("PresentList = %v, want nil entries skipped", list)
+	}
+}
+
+func TestPresenterMetadataKeys(t *testing.T) {
+	e := apierrors.New(400, "gql.v1.BAD", "bad", "").WithMetadata(map[string]string{"secret": "s", "field": "name"})
+	ge := Presenter(WithMetadataKeys("field"))(context.Background(), e)
+	md, _ := ge.Extensions[ExtensionMetadata].(map[string]string)
+	if md["field"] != "name" || md["secret"] != "" {
+		t.Errorf("extensions.metadata = %v, want only allowed keys", md)
+	}
+}
@@ -0,0 +1,93 @@
+// Package grpc
+//
+//	apierrors 的 grpc 拦截器
+package grpc
+
+import (
+	"context"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Option 拦截器选项
+type Option func(*options)
+
+type options struct {
+	errorID bool
+	mirror  *apierrors.Mirror
+}
+
+// WithErrorID 为返回给客户端的错误打上错误ID,见 apierrors.StampErrorID
+func WithErrorID() Option {
+	return func(o *options) {
+		o.errorID = true
+	}
+}
+
+// WithTrailers 将错误的 reason、code、错误ID等按 m 输出到 grpc trailer, m 为 nil 时使用 apierrors.DefaultMirror
+func WithTrailers(m *apierrors.Mirror) Option {
+	return func(o *options) {
+		if m == nil {
+			m = apierrors.DefaultMirror
+		}
+		o.mirror = m
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// convert 按选项处理服务端返回的错误
+func (o *options) convert(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if o.errorID {
+		return apierrors.StampErrorID(ctx, err)
+	}
+	return err
+}
+
+// trailer 返回需要输出的 trailer, 无需输出时返回 nil
+func (o *options) trailer(err error) metadata.MD {
+	if err == nil || o.mirror == nil {
+		return nil
+	}
+	headers := o.mirror.Headers(err)
+	if len(headers) == 0 {
+		return nil
+	}
+	return metadata.New(headers)
+}
+
+// UnaryServerInterceptor 服务端一元拦截器
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		err = o.convert(ctx, err)
+		if md := o.trailer(err); md != nil {
+			_ = grpc.SetTrailer(ctx, md)
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 服务端流拦截器
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := o.convert(ss.Context(), handler(srv, ss))
+		if md := o.trailer(err); md != nil {
+			ss.SetTrailer(md)
+		}
+		return err
+	}
+}
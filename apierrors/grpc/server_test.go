@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// serverStream 以 ctx 为上下文的 grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	trailer metadata.MD
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func (s *serverStream) SetTrailer(md metadata.MD) { s.trailer = metadata.Join(s.trailer, md) }
+
+// transportStream 记录一元调用设置的 trailer
+type transportStream struct {
+	trailer metadata.MD
+}
+
+func (s *transportStream) Method() string                  { return "/grpc.v1.Test/Unary" }
+func (s *transportStream) SetHeader(md metadata.MD) error  { return nil }
+func (s *transportStream) SendHeader(md metadata.MD) error { return nil }
+func (s *transportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// fixedID 将 DefaultIDGenerator 替换为返回 id 的生成器, 测试结束后恢复
+func fixedID(t *testing.T, id string) {
+	t.Helper()
+	old := apierrors.DefaultIDGenerator
+	apierrors.DefaultIDGenerator = func(ctx context.Context) string { return id }
+	t.Cleanup(func() { apierrors.DefaultIDGenerator = old })
+}
+
+func TestErrorID(t *testing.T) {
+	fixedID(t, "generated")
+	sentinel := apierrors.New(404, "grpc.v1.NOT_FOUND", "not found", "")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "absent", err: sentinel, want: "generated"},
+		{name: "present", err: sentinel.WithErrorID("upstream"), want: "upstream"},
+		{name: "nil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unary := UnaryServerInterceptor(WithErrorID())
+			_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+				return nil, tt.err
+			})
+			if got := apierrors.ErrorID(err); got != tt.want || (tt.err == nil) != (err == nil) {
+				t.Errorf("unary ErrorID = %q (err %v), want %q", got, err, tt.want)
+			}
+			stream := StreamServerInterceptor(WithErrorID())
+			err = stream(nil, &serverStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+				return tt.err
+			})
+			if got := apierrors.ErrorID(err); got != tt.want || (tt.err == nil) != (err == nil) {
+				t.Errorf("stream ErrorID = %q (err %v), want %q", got, err, tt.want)
+			}
+		})
+	}
+	if sentinel.ErrorID() != "" {
+		t.Errorf("interceptor modified the original error")
+	}
+}
+
+func TestWithoutErrorID(t *testing.T) {
+	fixedID(t, "generated")
+	unary := UnaryServerInterceptor()
+	_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return nil, apierrors.New(500, "grpc.v1.INTERNAL", "", "")
+	})
+	if id := apierrors.ErrorID(err); id != "" {
+		t.Errorf("ErrorID = %q, want none without WithErrorID", id)
+	}
+}
+
+func TestTrailers(t *testing.T) {
+	m := &apierrors.Mirror{
+		ReasonHeader:    "x-error-reason",
+		CodeHeader:      "x-error-code",
+		MetadataHeaders: map[string]string{"user": "x-error-user"},
+	}
+	e := apierrors.New(404, "grpc.v1.USER_NOT_FOUND", "", "").WithMetadata(map[string]string{"user": "张三"})
+	want := metadata.Pairs(
+		"x-error-reason", "grpc.v1.USER_NOT_FOUND",
+		"x-error-code", "404",
+		// 非 ASCII 值须百分号编码, 否则 grpc 拒绝发送
+		"x-error-user", "%E5%BC%A0%E4%B8%89",
+	)
+	check := func(t *testing.T, got metadata.MD) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Errorf("trailer = %v, want %v", got, want)
+		}
+		for k, v := range want {
+			if g := got.Get(k); len(g) != 1 || g[0] != v[0] {
+				t.Errorf("trailer %s = %q, want %q", k, g, v)
+			}
+		}
+	}
+	t.Run("unary", func(t *testing.T) {
+		ts := &transportStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), ts)
+		unary := UnaryServerInterceptor(WithTrailers(m))
+		_, _ = unary(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+			return nil, e
+		})
+		check(t, ts.trailer)
+	})
+	t.Run("stream", func(t *testing.T) {
+		ss := &serverStream{ctx: context.Background()}
+		stream := StreamServerInterceptor(WithTrailers(m))
+		_ = stream(nil, ss, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+			return e
+		})
+		check(t, ss.trailer)
+	})
+	t.Run("nil", func(t *testing.T) {
+		ss := &serverStream{ctx: context.Background()}
+		stream := StreamServerInterceptor(WithTrailers(nil))
+		_ = stream(nil, ss, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+			return nil
+		})
+		if ss.trailer != nil {
+			t.Errorf("trailer = %v, want none for nil error", ss.trailer)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package apierrors
+
+import (
+	status2 "github.com/alkaid/goerrors/apierrors/http/status"
+	"google.golang.org/grpc/codes"
+)
+
+// WithGRPCCode 显式设置 grpc 状态码, GRPCStatus 优先使用该状态码而非由 Code 转换
+//
+//	用于 FailedPrecondition、AlreadyExists、OutOfRange 等无法由 http 状态码转换得到的状态码.
+//	仅在本进程内有效,不会随 JSON 传输; grpc 客户端经 FromError 还原时保留.
+//	注意不会添加stack
+func (e *Error) WithGRPCCode(code codes.Code) *Error {
+	err := Clone(e)
+	err.grpcCode = code
+	return err
+}
+
+// GRPCCode returns the grpc code of current Error.
+//
+//	未显式设置时由 Code 经 status.ToGRPCCode 转换
+func (e *Error) GRPCCode() codes.Code {
+	if e.grpcCode != codes.OK {
+		return e.grpcCode
+	}
+	return status2.ToGRPCCode(int(e.Code))
+}
+
+// GRPCCode returns the grpc code for a particular error, err 为 nil 时返回 codes.OK.
+// It supports wrapped errors.
+func GRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	return FromError(err).GRPCCode()
+}
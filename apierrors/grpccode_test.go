@@ -0,0 +1,62 @@
+package apierrors
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGRPCCodeRoundTrip(t *testing.T) {
+	resetRegistry(t)
+	conflict := New(409, "grpccode.v1.ORDER_PAID", "order paid", "").WithGRPCCode(codes.FailedPrecondition)
+	Register(conflict)
+	tests := []struct {
+		name     string
+		err      *Error
+		wantCode int32
+		want     codes.Code
+	}{
+		// 已注册: http 状态码取注册的错误, grpc 状态码取 status
+		{name: "registered", err: conflict, wantCode: 409, want: codes.FailedPrecondition},
+		// 未注册: http 状态码由 grpc 状态码转换
+		{name: "unregistered", err: New(409, "grpccode.v1.EXISTS", "", "").WithGRPCCode(codes.AlreadyExists), wantCode: 409, want: codes.AlreadyExists},
+		{name: "derived", err: New(404, "grpccode.v1.NOT_FOUND", "", ""), wantCode: 404, want: codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.GRPCCode(); got != tt.want {
+				t.Fatalf("GRPCCode = %v, want %v", got, tt.want)
+			}
+			// 经序列化模拟 grpc 传输
+			b, err := proto.Marshal(tt.err.GRPCStatus().Proto())
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := status.New(codes.OK, "").Proto()
+			if err := proto.Unmarshal(b, s); err != nil {
+				t.Fatal(err)
+			}
+			remote := status.FromProto(s).Err()
+			if status.Code(remote) != tt.want {
+				t.Errorf("status code = %v, want %v", status.Code(remote), tt.want)
+			}
+			e := FromError(remote)
+			if e.GRPCCode() != tt.want || e.Code != tt.wantCode || e.Reason != tt.err.Reason {
+				t.Errorf("FromError = %v (grpc code %v), want code %d, grpc code %v", e, e.GRPCCode(), tt.wantCode, tt.want)
+			}
+			if got := GRPCCode(fmt.Errorf("wrap: %w", e)); got != tt.want {
+				t.Errorf("GRPCCode(wrapped) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if GRPCCode(nil) != codes.OK {
+		t.Errorf("GRPCCode(nil) = %v, want OK", GRPCCode(nil))
+	}
+	_ = conflict.WithGRPCCode(codes.Aborted)
+	if conflict.GRPCCode() != codes.FailedPrecondition {
+		t.Errorf("WithGRPCCode modified the original error")
+	}
+}
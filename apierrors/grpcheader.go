@@ -0,0 +1,113 @@
+package apierrors
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// grpc over http2 中表示错误的头(trailer)
+const (
+	HeaderGRPCStatus  = "grpc-status"
+	HeaderGRPCMessage = "grpc-message"
+	HeaderGRPCDetails = "grpc-status-details-bin"
+)
+
+// FromGRPCHeaders 从 grpc 响应头或 trailer 还原错误, grpc-status 不存在或为 0 时返回 nil
+//
+//	供只能拿到原始头的 http 代理、grpc-web BFF 使用. grpc-message 按 grpc 规范百分号解码,
+//	grpc-status-details-bin 中带 ErrorInfo 时按注册表还原, 与 FromError 处理 grpc status 的方式一致
+func FromGRPCHeaders(h http.Header) *Error {
+	v := h.Get(HeaderGRPCStatus)
+	if v == "" {
+		return nil
+	}
+	code, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		code = uint64(codes.Unknown)
+	}
+	if codes.Code(code) == codes.OK {
+		return nil
+	}
+	gs := status.New(codes.Code(code), decodeGRPCMessage(h.Get(HeaderGRPCMessage)))
+	if bin := h.Get(HeaderGRPCDetails); bin != "" {
+		if b, err := decodeBinHeader(bin); err == nil {
+			s := &spb.Status{}
+			if err := proto.Unmarshal(b, s); err == nil {
+				gs = status.FromProto(s)
+			}
+		}
+	}
+	return FromError(gs.Err())
+}
+
+// ToGRPCHeaders 返回 err 对应的 grpc 错误头, 用于手工构造与 grpc 兼容的响应, err 为 nil 时 grpc-status 为 0
+func ToGRPCHeaders(err error) http.Header {
+	h := http.Header{}
+	if err == nil {
+		h.Set(HeaderGRPCStatus, "0")
+		return h
+	}
+	s := FromError(err).GRPCStatus().Proto()
+	h.Set(HeaderGRPCStatus, strconv.Itoa(int(s.GetCode())))
+	if s.GetMessage() != "" {
+		h.Set(HeaderGRPCMessage, encodeGRPCMessage(s.GetMessage()))
+	}
+	if len(s.GetDetails()) > 0 {
+		if b, err := proto.Marshal(s); err == nil {
+			h.Set(HeaderGRPCDetails, base64.RawStdEncoding.EncodeToString(b))
+		}
+	}
+	return h
+}
+
+// decodeBinHeader 解码 -bin 头, 兼容有无 padding
+func decodeBinHeader(v string) ([]byte, error) {
+	if len(v)%4 == 0 {
+		return base64.StdEncoding.DecodeString(v)
+	}
+	return base64.RawStdEncoding.DecodeString(v)
+}
+
+const upperhex = "0123456789ABCDEF"
+
+// encodeGRPCMessage 按 grpc 规范对 grpc-message 百分号编码, 0x20~0x7E 以外的字节及 % 需要编码
+func encodeGRPCMessage(msg string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c >= ' ' && c <= '~' && c != '%' {
+			sb.WriteByte(c)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(upperhex[c>>4])
+		sb.WriteByte(upperhex[c&15])
+	}
+	return sb.String()
+}
+
+// decodeGRPCMessage 解码 grpc-message, 无效的 % 序列原样保留
+func decodeGRPCMessage(msg string) string {
+	if !strings.Contains(msg, "%") {
+		return msg
+	}
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		if msg[i] == '%' && i+2 < len(msg) {
+			if b, err := strconv.ParseUint(msg[i+1:i+3], 16, 8); err == nil {
+				sb.WriteByte(byte(b))
+				i += 2
+				continue
+			}
+		}
+		sb.WriteByte(msg[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,140 @@
+package apierrors
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestGRPCMessageEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     string
+		encoded string
+	}{
+		{name: "ascii", msg: "user not found", encoded: "user not found"},
+		{name: "percent", msg: "100%", encoded: "100%25"},
+		{name: "newline", msg: "a\nb", encoded: "a%0Ab"},
+		{name: "non-ascii", msg: "用户不存在", encoded: "%E7%94%A8%E6%88%B7%E4%B8%8D%E5%AD%98%E5%9C%A8"},
+		{name: "mixed", msg: "é 50% ✓", encoded: "%C3%A9 50%25 %E2%9C%93"},
+		{name: "empty", msg: "", encoded: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encodeGRPCMessage(tt.msg); got != tt.encoded {
+				t.Errorf("encodeGRPCMessage(%q) = %q, want %q", tt.msg, got, tt.encoded)
+			}
+			if got := decodeGRPCMessage(tt.encoded); got != tt.msg {
+				t.Errorf("decodeGRPCMessage(%q) = %q, want %q", tt.encoded, got, tt.msg)
+			}
+		})
+	}
+}
+
+func TestDecodeGRPCMessageInvalidEscapes(t *testing.T) {
+	tests := []struct {
+		encoded string
+		want    string
+	}{
+		{encoded: "%", want: "%"},
+		{encoded: "abc%", want: "abc%"},
+		{encoded: "abc%4", want: "abc%4"},
+		{encoded: "%zz", want: "%zz"},
+		{encoded: "%G1ok", want: "%G1ok"},
+		{encoded: "%+1", want: "%+1"},
+		{encoded: "%%41", want: "%A"},
+		{encoded: "50%% off", want: "50%% off"},
+		// 未编码的非 ASCII 原样保留
+		{encoded: "用户%20不存在", want: "用户 不存在"},
+	}
+	for _, tt := range tests {
+		if got := decodeGRPCMessage(tt.encoded); got != tt.want {
+			t.Errorf("decodeGRPCMessage(%q) = %q, want %q", tt.encoded, got, tt.want)
+		}
+	}
+}
+
+func TestGRPCHeadersRoundTrip(t *testing.T) {
+	resetRegistry(t)
+	registered := New(404, "user.v1.USER_NOT_FOUND", "user not found", "").WithDomain("user.example.com").WithBizCode(40401)
+	Register(registered)
+
+	h := ToGRPCHeaders(registered.WithMessage("用户 42 不存在").WithMetadata(map[string]string{"user_id": "42"}))
+	if got := h.Get(HeaderGRPCStatus); got != "5" {
+		t.Errorf("grpc-status = %q, want 5", got)
+	}
+	e := FromGRPCHeaders(h)
+	if e == nil || !Is(e, registered) {
+		t.Fatalf("FromGRPCHeaders = %v, want registered error", e)
+	}
+	if e.Message != "用户 42 不存在" || e.Metadata["user_id"] != "42" || e.BizCode != 40401 || e.GRPCCode() != codes.NotFound {
+		t.Errorf("FromGRPCHeaders = %v", e)
+	}
+	if ToGRPCHeaders(nil).Get(HeaderGRPCStatus) != "0" || FromGRPCHeaders(ToGRPCHeaders(nil)) != nil {
+		t.Errorf("nil error should round trip as grpc-status 0")
+	}
+}
+
+func TestFromGRPCHeadersMalformed(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		wantCode codes.Code
+		wantMsg  string
+		wantNil  bool
+	}{
+		{
+			name:    "no status",
+			header:  http.Header{"Grpc-Message": {"boom"}},
+			wantNil: true,
+		},
+		{
+			name:    "ok status",
+			header:  http.Header{"Grpc-Status": {"0"}},
+			wantNil: true,
+		},
+		{
+			name:     "non-numeric status",
+			header:   http.Header{"Grpc-Status": {"abc"}, "Grpc-Message": {"boom"}},
+			wantCode: codes.Unknown,
+			wantMsg:  "boom",
+		},
+		{
+			name:     "details not base64",
+			header:   http.Header{"Grpc-Status": {"3"}, "Grpc-Message": {"bad%20arg"}, "Grpc-Status-Details-Bin": {"!!!not base64!!!"}},
+			wantCode: codes.InvalidArgument,
+			wantMsg:  "bad arg",
+		},
+		{
+			name:     "details not a status proto",
+			header:   http.Header{"Grpc-Status": {"3"}, "Grpc-Message": {"bad"}, "Grpc-Status-Details-Bin": {base64.RawStdEncoding.EncodeToString([]byte{0xff, 0xff, 0xff})}},
+			wantCode: codes.InvalidArgument,
+			wantMsg:  "bad",
+		},
+		{
+			name:     "padded details",
+			header:   http.Header{"Grpc-Status": {"14"}, "Grpc-Status-Details-Bin": {base64.StdEncoding.EncodeToString([]byte{0x08, 0x0e, 0x12, 0x04, 'd', 'o', 'w', 'n'})}},
+			wantCode: codes.Unavailable,
+			wantMsg:  "down",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := FromGRPCHeaders(tt.header)
+			if tt.wantNil {
+				if e != nil {
+					t.Errorf("FromGRPCHeaders = %v, want nil", e)
+				}
+				return
+			}
+			if e == nil {
+				t.Fatal("FromGRPCHeaders = nil")
+			}
+			if e.GRPCCode() != tt.wantCode || e.Message != tt.wantMsg {
+				t.Errorf("FromGRPCHeaders = %v (grpc code %v), want %v %q", e, e.GRPCCode(), tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/alkaid/goerrors/apierrors/problem"
+)
+
+// acceptsProblem 判断 Accept 是否优先接受 application/problem+json
+//
+//	problem+json 的 q 值大于 0 且不低于 application/json 时返回 true
+func acceptsProblem(accept string) bool {
+	if accept == "" {
+		return false
+	}
+	problemQ, jsonQ := 0.0, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if f, perr := strconv.ParseFloat(v, 64); perr == nil {
+				q = f
+			}
+		}
+		switch mediaType {
+		case problem.ContentType:
+			problemQ = maxFloat(problemQ, q)
+		case ContentTypeJSON:
+			jsonQ = maxFloat(jsonQ, q)
+		}
+	}
+	return problemQ > 0 && problemQ >= jsonQ
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
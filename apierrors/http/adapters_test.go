@@ -0,0 +1,103 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+	apihttp "github.com/alkaid/goerrors/apierrors/http"
+	apichi "github.com/alkaid/goerrors/apierrors/http/chi"
+	apiecho "github.com/alkaid/goerrors/apierrors/http/echo"
+	apigin "github.com/alkaid/goerrors/apierrors/http/gin"
+	"github.com/alkaid/goerrors/apierrors/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// adapters 以各适配器处理返回 err 的请求
+var adapters = map[string]func(rd *apihttp.Renderer, err error) http.Handler{
+	"gin": func(rd *apihttp.Renderer, err error) http.Handler {
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.Use(apigin.ErrorHandler(rd))
+		r.GET("/users/1", apigin.Handler(func(c *gin.Context) error { return err }))
+		return r
+	},
+	"echo": func(rd *apihttp.Renderer, err error) http.Handler {
+		e := echo.New()
+		e.HTTPErrorHandler = apiecho.HTTPErrorHandler(rd)
+		e.GET("/users/1", func(c echo.Context) error { return err })
+		return e
+	},
+	"chi": func(rd *apihttp.Renderer, err error) http.Handler {
+		r := chi.NewRouter()
+		r.Get("/users/1", apichi.Handler(rd, func(w http.ResponseWriter, r *http.Request) error { return err }))
+		return r
+	},
+}
+
+func TestAdapters(t *testing.T) {
+	known := apierrors.New(404, "http.v1.USER_NOT_FOUND", "user not found", "用户不存在").
+		WithMetadata(map[string]string{"id": "1"})
+	unknown := errors.New("dial tcp 10.0.0.1:5432: connection refused")
+	redact := &apihttp.Renderer{Redact: apihttp.RedactUnknown}
+	tests := []struct {
+		name   string
+		rd     *apihttp.Renderer
+		err    error
+		accept string
+		// wantMessage 响应中的 message, 为空时不检查
+		wantMessage string
+	}{
+		{name: "known", err: known, wantMessage: "user not found"},
+		{name: "known problem", err: known, accept: problem.ContentType},
+		// 默认不脱敏
+		{name: "unknown", err: unknown, wantMessage: unknown.Error()},
+		{name: "unknown redacted", rd: redact, err: unknown, wantMessage: http.StatusText(http.StatusInternalServerError)},
+		{name: "known redacted", rd: redact, err: known, wantMessage: "user not found"},
+		{name: "unknown redacted problem", rd: redact, err: unknown, accept: problem.ContentType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := tt.rd
+			if rd == nil {
+				rd = apihttp.DefaultRenderer
+			}
+			newRequest := func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+				if tt.accept != "" {
+					r.Header.Set("Accept", tt.accept)
+				}
+				return r
+			}
+			// 以 Renderer 直接渲染的结果为基准, 各适配器的输出须完全一致
+			want := httptest.NewRecorder()
+			rd.Render(want, newRequest(), tt.err)
+			if tt.wantMessage != "" {
+				e, err := apihttp.UnmarshalStatus(want.Body.Bytes())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if e.Message != tt.wantMessage {
+					t.Errorf("Message = %q, want %q", e.Message, tt.wantMessage)
+				}
+			}
+			for name, adapter := range adapters {
+				w := httptest.NewRecorder()
+				adapter(tt.rd, tt.err).ServeHTTP(w, newRequest())
+				if w.Code != want.Code {
+					t.Errorf("%s: status = %d, want %d", name, w.Code, want.Code)
+				}
+				if got := w.Header().Get("Content-Type"); got != want.Header().Get("Content-Type") {
+					t.Errorf("%s: Content-Type = %q, want %q", name, got, want.Header().Get("Content-Type"))
+				}
+				if w.Body.String() != want.Body.String() {
+					t.Errorf("%s: body = %s, want %s", name, w.Body, want.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package chi
+//
+//	apierrors 的 chi 适配器. chi 使用标准 net/http 中间件,错误经 apihttp.Renderer 渲染
+package chi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alkaid/goerrors/apierrors"
+	apihttp "github.com/alkaid/goerrors/apierrors/http"
+	"github.com/alkaid/goerrors/errors"
+	"github.com/go-chi/chi/v5"
+)
+
+// Recoverer 返回中间件, 将 handler 的 panic 渲染为 500 错误, rd 为 nil 时使用 apihttp.DefaultRenderer
+//
+//	http.ErrAbortHandler 会继续 panic
+func Recoverer(rd *apihttp.Renderer) func(http.Handler) http.Handler {
+	if rd == nil {
+		rd = apihttp.DefaultRenderer
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler { //nolint:errorlint,goerr113 // 与 net/http 的处理一致
+					panic(rec)
+				}
+				err, ok := rec.(error)
+				if !ok {
+					err = fmt.Errorf("%v", rec)
+				}
+				rd.Render(w, r, apierrors.New(http.StatusInternalServerError, apierrors.UnknownReason, "panic", "").
+					WithCause(errors.WithStack(err)))
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Handler 将返回错误的 handler 适配为 http.HandlerFunc, rd 为 nil 时使用 apihttp.DefaultRenderer
+func Handler(rd *apihttp.Renderer, fn apihttp.HandlerFunc) http.HandlerFunc {
+	if rd == nil {
+		rd = apihttp.DefaultRenderer
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			rd.Render(w, r, err)
+		}
+	}
+}
+
+// RoutePattern 返回 chi 匹配的路由模板, 如 /users/{id}, 可用于 metrics.WithRoute
+func RoutePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return r.URL.Path
+}
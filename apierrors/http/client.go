@@ -0,0 +1,57 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"github.com/alkaid/goerrors/apierrors/problem"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// maxErrorBodySize 解析错误响应时读取 body 的上限
+const maxErrorBodySize = 1 << 20
+
+// ErrorFromResponse 从 http 响应中解析 WriteError 写出的错误,状态码小于 400 时返回 nil
+//
+//	支持 Status JSON 及 application/problem+json;
+//	reason 已注册时返回注册的错误并覆盖 message 及 metadata;
+//	body 无法解析时返回仅含状态码的错误.
+//	读取后会重置 resp.Body,调用方仍可再次读取
+func ErrorFromResponse(resp *http.Response) *apierrors.Error {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		resp.Body = replayBody{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == problem.ContentType {
+		p := &problem.Problem{}
+		if err := json.Unmarshal(body, p); err != nil {
+			return apierrors.New(resp.StatusCode, apierrors.UnknownReason, string(body), "")
+		}
+		if p.Status == 0 {
+			p.Status = resp.StatusCode
+		}
+		return ProblemCodec.Decode(p)
+	}
+	s := &apierrors.Status{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, s); err != nil {
+		return apierrors.New(resp.StatusCode, apierrors.UnknownReason, string(body), "")
+	}
+	if s.Code == 0 {
+		s.Code = int32(resp.StatusCode)
+	}
+	return apierrors.FromStatusRegistered(s)
+}
+
+// replayBody 重放已读取的部分,关闭时关闭原 body
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,46 @@
+// Package echo
+//
+//	apierrors 的 echo 适配器,错误经 apihttp.Renderer 渲染
+package echo
+
+import (
+	"fmt"
+
+	"github.com/alkaid/goerrors/apierrors"
+	apihttp "github.com/alkaid/goerrors/apierrors/http"
+	"github.com/labstack/echo/v4"
+)
+
+// HTTPErrorHandler 返回 echo.HTTPErrorHandler
+//
+//	*echo.HTTPError(如路由未找到)转换为 reason 为空的错误, 其余错误经 apierrors.FromError 转换.
+//	响应已写出时不做处理. rd 为 nil 时使用 apihttp.DefaultRenderer
+func HTTPErrorHandler(rd *apihttp.Renderer) echo.HTTPErrorHandler {
+	if rd == nil {
+		rd = apihttp.DefaultRenderer
+	}
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+		rd.Render(c.Response(), c.Request(), FromHTTPError(err))
+	}
+}
+
+// FromHTTPError 将 *echo.HTTPError 转换为 *apierrors.Error, 其他错误原样返回
+func FromHTTPError(err error) error {
+	he, ok := err.(*echo.HTTPError) //nolint:errorlint // echo 直接传入 *echo.HTTPError
+	if !ok {
+		return err
+	}
+	if he.Internal != nil {
+		if se := new(apierrors.Error); apierrors.As(he.Internal, &se) {
+			return he.Internal
+		}
+	}
+	msg, ok := he.Message.(string)
+	if !ok {
+		msg = fmt.Sprint(he.Message)
+	}
+	return apierrors.New(he.Code, apierrors.UnknownReason, msg, "").WithCause(he)
+}
@@ -0,0 +1,47 @@
+// Package gin
+//
+//	apierrors 的 gin 适配器,错误经 apihttp.Renderer 渲染
+package gin
+
+import (
+	apihttp "github.com/alkaid/goerrors/apierrors/http"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler 返回 gin 中间件, 在后续 handler 执行完毕后渲染 ctx.Error 记录的最后一个错误
+//
+//	响应已写出时不做处理. rd 为 nil 时使用 apihttp.DefaultRenderer
+func ErrorHandler(rd *apihttp.Renderer) gin.HandlerFunc {
+	if rd == nil {
+		rd = apihttp.DefaultRenderer
+	}
+	return func(c *gin.Context) {
+		c.Next()
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+		rd.Render(c.Writer, c.Request, last.Err)
+	}
+}
+
+// Abort 记录错误并中止后续 handler, 错误由 ErrorHandler 渲染
+func Abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// Handler 将返回错误的 handler 适配为 gin.HandlerFunc, 返回的错误经 Abort 交由 ErrorHandler 渲染
+func Handler(fn func(c *gin.Context) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := fn(c); err != nil {
+			Abort(c, err)
+		}
+	}
+}
+
+// Error 使用 apihttp.DefaultRenderer 立即渲染 err 并中止后续 handler
+func Error(c *gin.Context, err error) {
+	apihttp.WriteError(c.Writer, c.Request, err)
+	c.Abort()
+}
@@ -0,0 +1,119 @@
+// Package http
+//
+//	apierrors 的 http 错误输出及中间件
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"github.com/alkaid/goerrors/apierrors/problem"
+)
+
+// Decorator 在错误写出前对错误进行加工,返回加工后的错误
+//
+//	可在此设置响应头,但不可写入 body
+type Decorator func(w http.ResponseWriter, r *http.Request, e *apierrors.Error) *apierrors.Error
+
+// Observer 在错误写出时被通知,用于日志、trace、metrics 等
+type Observer func(r *http.Request, e *apierrors.Error)
+
+// HandlerFunc 可返回错误的 http.HandlerFunc,返回的错误由 WriteError 写出
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP implements http.Handler.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+// ContentTypeJSON Status JSON 的媒体类型
+const ContentTypeJSON = "application/json"
+
+// ProblemCodec WriteError 输出 problem details 时使用的转换器
+var ProblemCodec = problem.DefaultCodec
+
+type hooksKey struct{}
+
+type hooks struct {
+	decorators []Decorator
+	observers  []Observer
+}
+
+func hooksFrom(ctx context.Context) *hooks {
+	h, _ := ctx.Value(hooksKey{}).(*hooks)
+	if h == nil {
+		return &hooks{}
+	}
+	return h
+}
+
+func withHooks(next http.Handler, fn func(h *hooks)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		old := hooksFrom(r.Context())
+		h := &hooks{
+			decorators: append([]Decorator(nil), old.decorators...),
+			observers:  append([]Observer(nil), old.observers...),
+		}
+		fn(h)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), hooksKey{}, h)))
+	})
+}
+
+// Decorate 返回中间件,该中间件之后的 WriteError 会调用 d 加工错误
+//
+//	多个 Decorate 按中间件由外到内的顺序调用
+func Decorate(d Decorator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return withHooks(next, func(h *hooks) {
+			h.decorators = append(h.decorators, d)
+		})
+	}
+}
+
+// Observe 返回中间件,该中间件之后的 WriteError 会通知 o
+func Observe(o Observer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return withHooks(next, func(h *hooks) {
+			h.observers = append(h.observers, o)
+		})
+	}
+}
+
+// ErrorID 返回中间件,为写出的错误打上错误ID,见 apierrors.StampErrorID
+func ErrorID() func(http.Handler) http.Handler {
+	return Decorate(func(w http.ResponseWriter, r *http.Request, e *apierrors.Error) *apierrors.Error {
+		return apierrors.StampErrorID(r.Context(), e)
+	})
+}
+
+// WriteError 使用 DefaultRenderer 将 err 写入 w
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultRenderer.Render(w, r, err)
+}
+
+// StatusCode 返回 e 对应的 http 状态码
+func StatusCode(e *apierrors.Error) int {
+	code := int(e.Code)
+	if code < 100 || code > 599 {
+		return http.StatusInternalServerError
+	}
+	return code
+}
+
+// MirrorHeaders 返回中间件, 将写出错误的 reason、code、错误ID等按 m 输出到响应头, m 为 nil 时使用 apierrors.DefaultMirror
+//
+//	需要输出错误ID时应置于 ErrorID 中间件之内
+func MirrorHeaders(m *apierrors.Mirror) func(http.Handler) http.Handler {
+	if m == nil {
+		m = apierrors.DefaultMirror
+	}
+	return Decorate(func(w http.ResponseWriter, r *http.Request, e *apierrors.Error) *apierrors.Error {
+		for k, v := range m.Headers(e) {
+			w.Header().Set(k, v)
+		}
+		return e
+	})
+}
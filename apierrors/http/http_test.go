@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+// fixedID 将 DefaultIDGenerator 替换为返回 id 的生成器, 测试结束后恢复
+func fixedID(t *testing.T, id string) {
+	t.Helper()
+	old := apierrors.DefaultIDGenerator
+	apierrors.DefaultIDGenerator = func(ctx context.Context) string { return id }
+	t.Cleanup(func() { apierrors.DefaultIDGenerator = old })
+}
+
+// serve 以中间件 mw(由外到内)处理返回 err 的请求, 返回响应
+func serve(err error, mw ...func(http.Handler) http.Handler) *httptest.ResponseRecorder {
+	var h http.Handler = HandlerFunc(func(w http.ResponseWriter, r *http.Request) error { return err })
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w
+}
+
+func TestErrorID(t *testing.T) {
+	fixedID(t, "generated")
+	sentinel := apierrors.New(404, "http.v1.NOT_FOUND", "not found", "")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "absent", err: sentinel, want: "generated"},
+		{name: "present", err: sentinel.WithErrorID("upstream"), want: "upstream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var observed string
+			w := serve(tt.err, ErrorID(), Observe(func(r *http.Request, e *apierrors.Error) {
+				observed = e.ErrorID()
+			}))
+			if w.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want 404", w.Code)
+			}
+			e, err := UnmarshalStatus(w.Body.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.ErrorID() != tt.want || observed != tt.want {
+				t.Errorf("response, observed ErrorID = %q, %q, want %q", e.ErrorID(), observed, tt.want)
+			}
+		})
+	}
+	if sentinel.ErrorID() != "" {
+		t.Errorf("ErrorID middleware modified the original error")
+	}
+}
+
+func TestDecorateOrder(t *testing.T) {
+	var order []string
+	decorate := func(name string) func(http.Handler) http.Handler {
+		return Decorate(func(w http.ResponseWriter, r *http.Request, e *apierrors.Error) *apierrors.Error {
+			order = append(order, name)
+			return e.WithMetadata(map[string]string{"last": name})
+		})
+	}
+	var observed *apierrors.Error
+	w := serve(apierrors.New(400, "http.v1.BAD", "bad", ""), decorate("outer"), decorate("inner"), Observe(func(r *http.Request, e *apierrors.Error) {
+		observed = e
+	}))
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("decorators called in %v, want outer, inner", order)
+	}
+	if observed == nil || observed.Metadata["last"] != "inner" {
+		t.Errorf("observer got %v, want decorated error", observed)
+	}
+	if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != ContentTypeJSON {
+		t.Errorf("response = %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestWriteErrorNil(t *testing.T) {
+	w := serve(nil, ErrorID())
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("response = %d %q, want nothing written", w.Code, w.Body.String())
+	}
+}
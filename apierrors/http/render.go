@@ -0,0 +1,91 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"github.com/alkaid/goerrors/apierrors/problem"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Renderer 将错误渲染为 http 响应
+//
+//	gin/echo/chi 等适配器共用,保证 JSON 格式、状态码映射及脱敏行为一致
+type Renderer struct {
+	// Redact 写出前对错误脱敏, 为 nil 时不脱敏. Observer 收到的是脱敏前的错误
+	Redact func(e *apierrors.Error) *apierrors.Error
+}
+
+// DefaultRenderer WriteError 及各适配器默认使用的 Renderer, 默认不脱敏
+var DefaultRenderer = &Renderer{}
+
+// RedactUnknown 将未知错误(reason 为空,通常由普通 error 转换而来)的 message 替换为状态码的标准描述,
+// 避免将内部错误信息暴露给客户端. 需显式启用, 如 &Renderer{Redact: RedactUnknown}
+func RedactUnknown(e *apierrors.Error) *apierrors.Error {
+	if e.Reason != apierrors.UnknownReason {
+		return e
+	}
+	return e.WithMessage(http.StatusText(StatusCode(e))).WithCause(nil)
+}
+
+// Render 将 err 转换为 *apierrors.Error 并写入 w, err 为 nil 时不做处理
+//
+//	依次调用中间件注册的 Decorator、Observer 及 Redact.
+//	请求的 Accept 优先接受 application/problem+json 时按 RFC 9457 problem details 输出(见 ProblemCodec),
+//	否则以 Status JSON 输出. http 状态码取 Error.Code,非法时使用 500
+func (rd *Renderer) Render(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+	e := apierrors.FromError(err)
+	h := hooksFrom(r.Context())
+	for _, d := range h.decorators {
+		e = d(w, r, e)
+	}
+	for _, o := range h.observers {
+		o(r, e)
+	}
+	if rd.Redact != nil {
+		e = rd.Redact(e)
+	}
+	contentType := ContentTypeJSON
+	var body []byte
+	var merr error
+	if acceptsProblem(r.Header.Get("Accept")) {
+		contentType = problem.ContentType
+		body, merr = json.Marshal(ProblemCodec.Encode(e, r.URL.Path))
+	} else {
+		body, merr = MarshalStatus(e)
+	}
+	if merr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(StatusCode(e))
+	_, _ = w.Write(body)
+}
+
+// MarshalStatus 以 protojson 编码 Status 并去除空白, 保证输出稳定且不含换行
+func MarshalStatus(e *apierrors.Error) ([]byte, error) {
+	body, err := protojson.Marshal(&e.Status)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, body); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalStatus 解码 MarshalStatus 编码的 Status, 经注册表还原错误
+func UnmarshalStatus(body []byte) (*apierrors.Error, error) {
+	s := &apierrors.Status{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, s); err != nil {
+		return nil, err
+	}
+	return apierrors.FromStatusRegistered(s), nil
+}
@@ -0,0 +1,82 @@
+// Package sse
+//
+//	Server-Sent Events 中 apierrors.Error 的 error 事件编解码
+//	See: https://html.spec.whatwg.org/multipage/server-sent-events.html
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/alkaid/goerrors/apierrors"
+	apihttp "github.com/alkaid/goerrors/apierrors/http"
+)
+
+// EventError error 事件的事件名
+const EventError = "error"
+
+// Encode 将 err 编码为 "event: error" 帧, data 为单行的 Status JSON; err 为 nil 时返回 nil
+func Encode(err error) ([]byte, error) {
+	if err == nil {
+		return nil, nil
+	}
+	data, merr := apihttp.MarshalStatus(apierrors.FromError(err))
+	if merr != nil {
+		return nil, merr
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteString("event: " + EventError + "\n")
+	buf.WriteString("data: ")
+	buf.Write(data)
+	buf.WriteString("\n\n")
+	return buf.Bytes(), nil
+}
+
+// WriteError 将 err 以 error 事件写入 w, w 实现 http.Flusher 时立即 flush; err 为 nil 时不写入
+func WriteError(w http.ResponseWriter, err error) error {
+	if err == nil {
+		return nil
+	}
+	frame, eerr := Encode(err)
+	if eerr != nil {
+		return eerr
+	}
+	if _, werr := w.Write(frame); werr != nil {
+		return werr
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// Decode 解码 error 事件的 data, 经注册表还原错误
+func Decode(data []byte) (*apierrors.Error, error) {
+	return apihttp.UnmarshalStatus(data)
+}
+
+// DecodeFrame 解析一个完整的事件帧, 是 error 事件时返回还原的错误, 否则返回 nil
+func DecodeFrame(frame []byte) (*apierrors.Error, error) {
+	event := ""
+	var data []string
+	sc := bufio.NewScanner(bytes.NewReader(frame))
+	for sc.Scan() {
+		field, value, _ := strings.Cut(sc.Text(), ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if event != EventError {
+		return nil, nil
+	}
+	return Decode([]byte(strings.Join(data, "\n")))
+}
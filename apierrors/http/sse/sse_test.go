@@ -0,0 +1,35 @@
+package sse
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+func TestNilError(t *testing.T) {
+	if frame, err := Encode(nil); frame != nil || err != nil {
+		t.Errorf("Encode(nil) = %q, %v, want nil, nil", frame, err)
+	}
+	w := httptest.NewRecorder()
+	if err := WriteError(w, nil); err != nil {
+		t.Errorf("WriteError(nil) = %v", err)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("WriteError(nil) wrote %q", w.Body.String())
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := WriteError(w, apierrors.New(404, "sse.v1.NOT_FOUND", "not found", "")); err != nil {
+		t.Fatal(err)
+	}
+	e, err := DecodeFrame(w.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e == nil || e.Code != 404 || e.Reason != "sse.v1.NOT_FOUND" || e.Message != "not found" {
+		t.Errorf("DecodeFrame(%q) = %v", w.Body.String(), e)
+	}
+}
@@ -0,0 +1,117 @@
+// Package websocket
+//
+//	WebSocket close 帧中 apierrors.Error 的编解码
+//	close code 为 CloseCodeOffset + Error.Code(如 404 对应 4404), close reason 为 Error.Reason
+//	See: https://www.rfc-editor.org/rfc/rfc6455#section-5.5.1
+package websocket
+
+import (
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+// close code, See: https://www.rfc-editor.org/rfc/rfc6455#section-7.4.1
+const (
+	CloseNormalClosure   = 1000
+	CloseGoingAway       = 1001
+	ClosePolicyViolation = 1008
+	CloseInternalError   = 1011
+	// CloseCodeOffset 私有 close code 区间 [4000,4999] 的起点
+	CloseCodeOffset = 4000
+	// MaxCloseTextSize close reason 的最大字节数
+	MaxCloseTextSize = 123
+	// BizCodePrefix close reason 为业务错误码时的前缀, reason 不含该字符, 不会与 reason 混淆
+	BizCodePrefix = "#"
+)
+
+// ErrInvalidCloseFrame close 帧 payload 非法
+var ErrInvalidCloseFrame = errors.New("websocket: invalid close frame")
+
+// CloseCode 返回 err 对应的 close code
+//
+//	Error.Code 在 [100,999] 内时为 CloseCodeOffset + Code, 否则为 CloseInternalError; err 为 nil 时为 CloseNormalClosure
+func CloseCode(err error) int {
+	if err == nil {
+		return CloseNormalClosure
+	}
+	code := int(apierrors.FromError(err).Code)
+	if code < 100 || code > 999 {
+		return CloseInternalError
+	}
+	return CloseCodeOffset + code
+}
+
+// CloseText 返回 err 对应的 close reason
+//
+//	为 Error.Reason; 超过 MaxCloseTextSize 字节时为 BizCodePrefix 加十进制的业务错误码(如 #40401), 未设置业务错误码时为空
+func CloseText(err error) string {
+	if err == nil {
+		return ""
+	}
+	e := apierrors.FromError(err)
+	if len(e.Reason) <= MaxCloseTextSize {
+		return e.Reason
+	}
+	if e.BizCode != 0 {
+		return BizCodePrefix + strconv.Itoa(int(e.BizCode))
+	}
+	return ""
+}
+
+// CloseFrame 返回 err 对应的 close 帧 payload: 2 字节大端 close code 及 close reason
+func CloseFrame(err error) []byte {
+	text := CloseText(err)
+	payload := make([]byte, 2, 2+len(text))
+	binary.BigEndian.PutUint16(payload, uint16(CloseCode(err)))
+	return append(payload, text...)
+}
+
+// FromClose 由 close code 及 close reason 还原错误, CloseNormalClosure 返回 nil
+//
+//	reason 已注册时返回注册错误的副本; 以 BizCodePrefix 开头时视为业务错误码, 其余原样作为 reason
+func FromClose(code int, text string) *apierrors.Error {
+	if code == CloseNormalClosure {
+		return nil
+	}
+	if e, ok := apierrors.Lookup(text); ok {
+		return apierrors.Clone(e)
+	}
+	// reason 过长时 CloseText 以业务错误码代替
+	var bizCode int64
+	if strings.HasPrefix(text, BizCodePrefix) {
+		if n, err := strconv.ParseInt(text[len(BizCodePrefix):], 10, 32); err == nil {
+			bizCode, text = n, ""
+		}
+	}
+	httpCode := http.StatusInternalServerError
+	switch {
+	case code >= CloseCodeOffset+100 && code < CloseCodeOffset+1000:
+		httpCode = code - CloseCodeOffset
+	case code == CloseGoingAway:
+		httpCode = http.StatusServiceUnavailable
+	case code == ClosePolicyViolation:
+		httpCode = http.StatusForbidden
+	}
+	e := apierrors.New(httpCode, text, "", "")
+	if bizCode != 0 {
+		e = e.WithBizCode(int32(bizCode))
+	}
+	return e
+}
+
+// FromCloseFrame 由 close 帧 payload 还原错误, payload 为空时视为 CloseNormalClosure
+func FromCloseFrame(payload []byte) (*apierrors.Error, error) {
+	if len(payload) == 0 {
+		return nil, nil
+	}
+	if len(payload) < 2 || !utf8.Valid(payload[2:]) {
+		return nil, ErrInvalidCloseFrame
+	}
+	return FromClose(int(binary.BigEndian.Uint16(payload)), string(payload[2:])), nil
+}
@@ -0,0 +1,95 @@
+package websocket
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+func TestCloseFrameRoundTrip(t *testing.T) {
+	longPkg := strings.Repeat("very.long.package.", 8)
+	registered := apierrors.New(404, "ws.v1.WS_USER_NOT_FOUND", "user not found", "")
+	apierrors.Register(registered)
+	tests := []struct {
+		name     string
+		err      error
+		wantText string
+		wantCode int32
+		wantBiz  int32
+		// wantIs 还原结果应匹配的错误, 为 nil 时不检查
+		wantIs *apierrors.Error
+	}{
+		{
+			name:     "short reason",
+			err:      apierrors.New(400, "ws.v1.BAD", "", ""),
+			wantText: "ws.v1.BAD",
+			wantCode: 400,
+		},
+		{
+			name:     "registered reason",
+			err:      registered,
+			wantText: "ws.v1.WS_USER_NOT_FOUND",
+			wantCode: 404,
+			wantIs:   registered,
+		},
+		{
+			name:     "long reason falls back to biz code",
+			err:      apierrors.New(409, longPkg+"WS_CONFLICT", "", "").WithBizCode(40901),
+			wantText: "#40901",
+			wantCode: 409,
+			wantBiz:  40901,
+		},
+		{
+			name:     "numeric reason",
+			err:      apierrors.New(400, "42", "", "").WithBizCode(40001),
+			wantText: "42",
+			wantCode: 400,
+		},
+		{
+			name:     "long reason without biz code",
+			err:      apierrors.New(409, strings.Repeat("冲突", 50), "", ""),
+			wantText: "",
+			wantCode: 409,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CloseText(tt.err); got != tt.wantText {
+				t.Fatalf("CloseText = %q, want %q", got, tt.wantText)
+			}
+			e, err := FromCloseFrame(CloseFrame(tt.err))
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantReason := tt.wantText
+			if tt.wantBiz != 0 {
+				wantReason = ""
+			}
+			if tt.wantIs != nil {
+				wantReason = tt.wantIs.Reason
+			}
+			if e.Code != tt.wantCode || e.BizCode != tt.wantBiz || e.Reason != wantReason {
+				t.Errorf("FromCloseFrame = %v, want code %d reason %q biz_code %d", e, tt.wantCode, wantReason, tt.wantBiz)
+			}
+			if tt.wantIs != nil && !errors.Is(e, tt.wantIs) {
+				t.Errorf("FromCloseFrame = %v, want registered error", e)
+			}
+		})
+	}
+}
+
+func TestFromCloseReturnsCopy(t *testing.T) {
+	registered := apierrors.New(403, "ws.v1.WS_FORBIDDEN", "forbidden", "")
+	apierrors.Register(registered)
+	e := FromClose(CloseCodeOffset+403, "ws.v1.WS_FORBIDDEN")
+	if e == registered {
+		t.Fatal("FromClose returned the registered error itself")
+	}
+	e.Metadata["k"] = "v"
+	e.Message = "changed"
+	if got, _ := apierrors.Lookup("ws.v1.WS_FORBIDDEN"); got.Message != "forbidden" || len(got.Metadata) != 0 {
+		t.Errorf("registered error changed to %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+package apierrors
+
+import (
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// JSONCodec Error 的 JSON 编解码
+//
+//	字段名与 Status 的 protojson 字段名一致,cause 链以嵌套的 "cause" 字段输出
+type JSONCodec struct {
+	// Cause 是否输出 cause 链
+	Cause bool
+	// Sanitize 返回非 *Error 的 cause 输出的消息, 为 nil 时使用 err.Error()
+	//  cause 可能包含敏感信息,持久化或对外输出前可在此脱敏
+	Sanitize func(err error) string
+}
+
+// DefaultJSONCodec Error.MarshalJSON 及 Error.UnmarshalJSON 使用的编解码器
+//
+//	应在 init 或程序启动时配置, 编解码过程中修改其字段或替换该变量不是并发安全的
+var DefaultJSONCodec = &JSONCodec{}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return DefaultJSONCodec.Marshal(e)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+//	reason 已注册时以注册的错误为基础,Is 仍可匹配
+func (e *Error) UnmarshalJSON(data []byte) error {
+	return DefaultJSONCodec.Unmarshal(data, e)
+}
+
+// Marshal 将 e 编码为 JSON, 输出紧凑且字段按名称排序
+func (c *JSONCodec) Marshal(e *Error) ([]byte, error) {
+	fields, err := c.fields(e)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// fields 返回 e 的 JSON 字段, Status 的字段取自 protojson 的输出, cause 为嵌套的字段
+//
+//	protojson 输出中的空白不稳定, 解析为字段后由 encoding/json 统一编码
+func (c *JSONCodec) fields(e *Error) (map[string]any, error) {
+	body, err := protojson.Marshal(&e.Status)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]any, len(raw)+1)
+	for k, v := range raw {
+		fields[k] = v
+	}
+	if !c.Cause || e.cause == nil {
+		return fields, nil
+	}
+	if fields["cause"], err = c.causeFields(e.cause); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (c *JSONCodec) causeFields(cause error) (map[string]any, error) {
+	if se := new(Error); errors.As(cause, &se) {
+		return c.fields(se)
+	}
+	msg := cause.Error()
+	if c.Sanitize != nil {
+		msg = c.Sanitize(cause)
+	}
+	return map[string]any{"message": msg}, nil
+}
+
+// Unmarshal 将 JSON 解码到 e
+//
+//	reason 已注册时以注册的错误为基础,覆盖 message 及 metadata
+func (c *JSONCodec) Unmarshal(data []byte, e *Error) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	rawCause := fields["cause"]
+	delete(fields, "cause")
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	s := &Status{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, s); err != nil {
+		return err
+	}
+	ne := FromStatusWithoutStack(s)
+	if re, ok := LookupDomain(s.Domain, s.Reason); ok {
+		ne = re.WithMessage(s.Message).WithMetadata(s.Metadata)
+		if s.Pretty != "" {
+			ne.Pretty = s.Pretty
+		}
+		if s.BizCode != 0 {
+			ne.BizCode = s.BizCode
+		}
+	}
+	if len(rawCause) > 0 && string(rawCause) != "null" {
+		cause, err := c.unmarshalCause(rawCause)
+		if err != nil {
+			return err
+		}
+		ne.cause = cause
+	}
+	e.assign(ne)
+	return nil
+}
+
+// unmarshalCause 解码 cause, 含 code 或 reason 时解码为 *Error
+func (c *JSONCodec) unmarshalCause(data []byte) (cause, err error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	_, hasCode := fields["code"]
+	_, hasReason := fields["reason"]
+	if hasCode || hasReason {
+		se := &Error{}
+		if err := c.Unmarshal(data, se); err != nil {
+			return nil, err
+		}
+		return se, nil
+	}
+	var msg string
+	if err := json.Unmarshal(fields["message"], &msg); err != nil {
+		return nil, err
+	}
+	return errors.New(msg), nil
+}
@@ -0,0 +1,138 @@
+package apierrors
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// resetRegistry 清空注册表, 测试结束后恢复
+func resetRegistry(t *testing.T) {
+	t.Helper()
+	oldErrs, oldReasonErrs, oldShortErrs := errs, reasonErrs, shortErrs
+	errs, reasonErrs, shortErrs = map[errKey]*Error{}, map[string][]*Error{}, map[string][]*Error{}
+	t.Cleanup(func() {
+		errs, reasonErrs, shortErrs = oldErrs, oldReasonErrs, oldShortErrs
+	})
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	resetRegistry(t)
+	registered := New(404, "user.v1.USER_NOT_FOUND", "user not found", "no such user").
+		WithDomain("user.example.com").
+		WithBizCode(40401).
+		WithSeverity(Level_INFO).
+		WithExpected(true)
+	Register(registered)
+
+	tests := []struct {
+		name         string
+		err          *Error
+		wantSeverity Level
+		wantExpected bool
+		// wantIs 解码结果应匹配的错误, 为 nil 时不检查
+		wantIs *Error
+	}{
+		{
+			name:         "registered with metadata",
+			err:          registered.WithMessage("user 42 not found").WithMetadata(map[string]string{"user_id": "42"}),
+			wantSeverity: Level_INFO,
+			wantExpected: true,
+			wantIs:       registered,
+		},
+		{
+			name:         "registered overrides biz_code",
+			err:          registered.WithBizCode(40402),
+			wantSeverity: Level_INFO,
+			wantExpected: true,
+			wantIs:       registered,
+		},
+		{
+			name: "unknown reason",
+			err: New(409, "order.v1.CONFLICT", "order conflict", "try again").
+				WithDomain("order.example.com").
+				WithBizCode(40901).
+				WithMetadata(map[string]string{"order_id": "7"}).
+				WithSeverity(Level_CRITICAL).
+				WithExpected(true),
+			// severity 及 expected 不随错误传输, 未注册时使用缺省值
+			wantSeverity: Level_WARN,
+		},
+		{
+			name:         "unknown reason without optional fields",
+			err:          New(500, "", "boom", ""),
+			wantSeverity: Level_ERROR,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.err)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			got := &Error{}
+			if err := json.Unmarshal(data, got); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", data, err)
+			}
+			if got.Code != tt.err.Code || got.Reason != tt.err.Reason || got.Message != tt.err.Message ||
+				got.Pretty != tt.err.Pretty || got.BizCode != tt.err.BizCode || got.Domain != tt.err.Domain {
+				t.Errorf("round trip of %s = %v, want %v", data, &got.Status, &tt.err.Status)
+			}
+			wantMD := tt.err.Metadata
+			if len(wantMD) == 0 {
+				wantMD = map[string]string{}
+			}
+			if !reflect.DeepEqual(got.Metadata, wantMD) {
+				t.Errorf("metadata = %v, want %v", got.Metadata, wantMD)
+			}
+			if got.Severity() != tt.wantSeverity || got.Expected() != tt.wantExpected {
+				t.Errorf("severity, expected = %v, %v, want %v, %v", got.Severity(), got.Expected(), tt.wantSeverity, tt.wantExpected)
+			}
+			if tt.wantIs != nil && !errors.Is(got, tt.wantIs) {
+				t.Errorf("decoded error does not match registered error")
+			}
+		})
+	}
+}
+
+func TestJSONCause(t *testing.T) {
+	resetRegistry(t)
+	codec := &JSONCodec{Cause: true, Sanitize: func(err error) string { return "hidden" }}
+	inner := New(400, "a.v1.BAD", "bad", "")
+	e := New(500, "a.v1.OUTER", "outer", "").WithCause(inner.WithCause(errors.New("secret")))
+	data, err := codec.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &Error{}
+	if err := codec.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	cause := FromError(got.Unwrap())
+	if cause.Reason != "a.v1.BAD" || cause.Code != 400 {
+		t.Fatalf("cause = %v, want a.v1.BAD", cause)
+	}
+	if leaf := cause.Unwrap(); leaf == nil || leaf.Error() != "hidden" {
+		t.Errorf("leaf cause = %v, want sanitized message", leaf)
+	}
+}
+
+func TestJSONCompact(t *testing.T) {
+	codec := &JSONCodec{Cause: true}
+	e := New(500, "a.v1.OUTER", "outer", "").
+		WithMetadata(map[string]string{"b": "2", "a": "1"}).
+		WithCause(New(400, "a.v1.BAD", "bad", "").WithCause(errors.New("leaf")))
+	data, err := codec.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"cause":{"cause":{"message":"leaf"},"code":400,"message":"bad","reason":"a.v1.BAD"},` +
+		`"code":500,"message":"outer","metadata":{"a":"1","b":"2"},"reason":"a.v1.OUTER"}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+	if data, err = json.Marshal(New(404, "", "", "")); err != nil || string(data) != `{"code":404}` {
+		t.Errorf("Marshal = %s, %v, want compact output", data, err)
+	}
+}
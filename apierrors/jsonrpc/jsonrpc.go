@@ -0,0 +1,167 @@
+// Package jsonrpc
+//
+//	apierrors 与 JSON-RPC 2.0 error object 之间的转换
+//	See: https://www.jsonrpc.org/specification#error_object
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+// JSON-RPC 2.0 预定义错误码
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeServerError 实现自定义服务端错误码区间 [-32099,-32000] 的上界
+	CodeServerError = -32000
+)
+
+// Error JSON-RPC 2.0 error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    *Data  `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc: code=%d,message=%s", e.Code, e.Message)
+}
+
+// Data error object 的 data 成员,携带 apierrors.Error 的信息
+type Data struct {
+	// Code http 风格的错误码,即 Error.Code
+	Code     int32             `json:"code"`
+	Reason   string            `json:"reason,omitempty"`
+	Pretty   string            `json:"pretty,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Mapper http 风格错误码与 JSON-RPC 错误码之间的转换
+type Mapper interface {
+	// ToJSONRPC converts an HTTP error code into the corresponding JSON-RPC error code.
+	ToJSONRPC(code int) int
+
+	// FromJSONRPC converts a JSON-RPC error code into the corresponding HTTP error code.
+	FromJSONRPC(code int) int
+}
+
+type mapper struct{}
+
+// DefaultMapper default mapper.
+//
+//	400 为 InvalidParams, 501 为 MethodNotFound, 500 为 InternalError, 其余为 ServerError
+var DefaultMapper Mapper = mapper{}
+
+func (m mapper) ToJSONRPC(code int) int {
+	switch code {
+	case http.StatusBadRequest:
+		return CodeInvalidParams
+	case http.StatusNotImplemented:
+		return CodeMethodNotFound
+	case http.StatusInternalServerError:
+		return CodeInternalError
+	}
+	return CodeServerError
+}
+
+func (m mapper) FromJSONRPC(code int) int {
+	switch code {
+	case CodeParseError, CodeInvalidRequest, CodeInvalidParams:
+		return http.StatusBadRequest
+	case CodeMethodNotFound:
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
+
+// Codec *apierrors.Error 与 JSON-RPC error object 之间的转换
+type Codec struct {
+	Mapper Mapper
+}
+
+// DefaultCodec 使用 DefaultMapper 的转换器
+var DefaultCodec = &Codec{Mapper: DefaultMapper}
+
+// Encode 将 err 转换为 JSON-RPC error object, err 为 nil 时返回 nil
+//
+//	*Error 原样返回
+func (c *Codec) Encode(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	if je, ok := err.(*Error); ok { //nolint:errorlint // 仅处理未包装的 *Error
+		return je
+	}
+	e := apierrors.FromError(err)
+	// 复制 metadata, 修改返回的 data 不会影响 err(可能是已注册的错误)
+	var md map[string]string
+	if len(e.Metadata) > 0 {
+		md = make(map[string]string, len(e.Metadata))
+		for k, v := range e.Metadata {
+			md[k] = v
+		}
+	}
+	return &Error{
+		Code:    c.Mapper.ToJSONRPC(int(e.Code)),
+		Message: e.Message,
+		Data: &Data{
+			Code:     e.Code,
+			Reason:   e.Reason,
+			Pretty:   e.Pretty,
+			Metadata: md,
+		},
+	}
+}
+
+// Decode 将 JSON-RPC error object 转换为 *apierrors.Error
+//
+//	reason 已注册时返回注册的错误并覆盖 message 及 metadata;
+//	不含 data 时由 Mapper 还原错误码
+func (c *Codec) Decode(je *Error) *apierrors.Error {
+	if je == nil {
+		return nil
+	}
+	data := je.Data
+	if data == nil {
+		data = &Data{}
+	}
+	if e, ok := apierrors.Lookup(data.Reason); ok {
+		return e.WithMessage(je.Message).WithMetadata(data.Metadata)
+	}
+	code := int(data.Code)
+	if code == 0 {
+		code = c.Mapper.FromJSONRPC(je.Code)
+	}
+	return apierrors.New(code, data.Reason, je.Message, data.Pretty).WithMetadata(data.Metadata).WithCause(je)
+}
+
+// Unmarshal 从 JSON 编码的 error object 还原 *apierrors.Error
+func (c *Codec) Unmarshal(data []byte) (*apierrors.Error, error) {
+	je := &Error{}
+	if err := json.Unmarshal(data, je); err != nil {
+		return nil, err
+	}
+	return c.Decode(je), nil
+}
+
+// Encode 使用 DefaultCodec 将 err 转换为 JSON-RPC error object
+func Encode(err error) *Error {
+	return DefaultCodec.Encode(err)
+}
+
+// Decode 使用 DefaultCodec 将 JSON-RPC error object 转换为 *apierrors.Error
+func Decode(je *Error) *apierrors.Error {
+	return DefaultCodec.Decode(je)
+}
+
+// Unmarshal 使用 DefaultCodec 从 JSON 编码的 error object 还原 *apierrors.Error
+func Unmarshal(data []byte) (*apierrors.Error, error) {
+	return DefaultCodec.Unmarshal(data)
+}
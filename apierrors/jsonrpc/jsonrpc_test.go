@@ -0,0 +1,99 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+func TestRoundTrip(t *testing.T) {
+	registered := apierrors.New(404, "rpc.v1.RPC_NOT_FOUND", "not found", "no such item").
+		WithMetadata(map[string]string{"kind": "item"})
+	apierrors.Register(registered)
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		// wantIs 还原结果应匹配的错误, 为 nil 时不检查
+		wantIs *apierrors.Error
+	}{
+		{
+			name:     "registered",
+			err:      registered.WithMessage("item 7 not found").WithMetadata(map[string]string{"id": "7"}),
+			wantCode: CodeServerError,
+			wantIs:   registered,
+		},
+		{
+			name:     "invalid params",
+			err:      apierrors.New(400, "rpc.v1.BAD", "bad", "bad request"),
+			wantCode: CodeInvalidParams,
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("boom"),
+			wantCode: CodeInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := apierrors.FromError(tt.err)
+			je := Encode(tt.err)
+			if je.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", je.Code, tt.wantCode)
+			}
+			data, err := json.Marshal(je)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Unmarshal(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Code != want.Code || got.Reason != want.Reason || got.Message != want.Message || got.Pretty != want.Pretty {
+				t.Errorf("round trip of %s = %v, want %v", data, got, want)
+			}
+			for k, v := range want.Metadata {
+				if got.Metadata[k] != v {
+					t.Errorf("metadata[%q] = %q, want %q", k, got.Metadata[k], v)
+				}
+			}
+			if tt.wantIs != nil && !errors.Is(got, tt.wantIs) {
+				t.Errorf("decoded error does not match registered error")
+			}
+		})
+	}
+}
+
+func TestEncodeCopiesMetadata(t *testing.T) {
+	registered := apierrors.New(409, "rpc.v1.RPC_CONFLICT", "conflict", "").
+		WithMetadata(map[string]string{"kind": "item"})
+	apierrors.Register(registered)
+	je := Encode(registered)
+	je.Data.Metadata["domain"] = "rpc.example.com"
+	if len(registered.Metadata) != 1 {
+		t.Errorf("registered metadata changed to %v", registered.Metadata)
+	}
+}
+
+func TestDecodeWithoutData(t *testing.T) {
+	tests := []struct {
+		code int
+		want int32
+	}{
+		{code: CodeParseError, want: 400},
+		{code: CodeMethodNotFound, want: 501},
+		{code: CodeInternalError, want: 500},
+		{code: -32001, want: 500},
+	}
+	for _, tt := range tests {
+		e := Decode(&Error{Code: tt.code, Message: "m"})
+		if e.Code != tt.want || e.Message != "m" {
+			t.Errorf("Decode(%d) = %v, want code %d", tt.code, e, tt.want)
+		}
+	}
+	if Decode(nil) != nil || Encode(nil) != nil {
+		t.Errorf("nil should stay nil")
+	}
+}
@@ -0,0 +1,76 @@
+package apierrors
+
+import (
+	"errors"
+	"reflect"
+)
+
+// kratosErrorsPath kratos 错误包的导入路径, 仅该包的 *Error 会被 fromKratos 识别
+var kratosErrorsPath = "github.com/go-kratos/kratos/v2/errors"
+
+// kratosError kratos/v2/errors.Error 的方法集
+type kratosError interface {
+	error
+	GetCode() int32
+	GetReason() string
+	GetMessage() string
+	GetMetadata() map[string]string
+}
+
+// asKratos 返回 err 链中的 kratos *errors.Error
+//
+//	按具体类型识别, 无需依赖 kratos; 仅具有相同方法集的其他错误(如其他 status 风格的错误)不会被识别
+func asKratos(err error) (kratosError, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		ke, ok := err.(kratosError) //nolint:errorlint // 逐层检查具体类型
+		if !ok {
+			continue
+		}
+		if t := reflect.TypeOf(ke); t.Kind() == reflect.Pointer && t.Elem().Name() == "Error" && t.Elem().PkgPath() == kratosErrorsPath {
+			return ke, true
+		}
+	}
+	return nil, false
+}
+
+// fromKratos 将 kratos *errors.Error 转换为 *Error
+//
+//	kratos 以枚举值名(如 USER_NOT_FOUND)作为 reason, 未按 reason 找到时按枚举值名在已注册的错误中查找(仅唯一匹配),
+//	可匹配本库以全名注册的错误
+func fromKratos(err error) (*Error, bool) {
+	ke, ok := asKratos(err)
+	if !ok {
+		return nil, false
+	}
+	e, ok := Lookup(ke.GetReason())
+	if !ok {
+		e, ok = lookupShort(ke.GetReason())
+	}
+	if ok {
+		return e.WithMessage(ke.GetMessage()).WithMetadata(ke.GetMetadata()), true
+	}
+	return New(int(ke.GetCode()), ke.GetReason(), ke.GetMessage(), "").WithMetadata(ke.GetMetadata()).WithCause(err), true
+}
+
+// KratosError kratos/v2/errors.Error 的方法集, 供 ToKratos 使用
+type KratosError[E any] interface {
+	error
+	WithMetadata(md map[string]string) E
+}
+
+// ToKratos 将 err 转换为 kratos 错误, err 为 nil 时返回零值
+//
+//	newFn 传入 kratos 的 errors.New, 如 apierrors.ToKratos(err, kerrors.New).
+//	reason 转换为 kratos 使用的枚举值名(如 test.USER_NOT_FOUND 转换为 USER_NOT_FOUND), Pretty 不会保留
+func ToKratos[E KratosError[E]](err error, newFn func(code int, reason, message string) E) E {
+	var ke E
+	if err == nil {
+		return ke
+	}
+	e := FromError(err)
+	ke = newFn(int(e.Code), shortReason(e.Reason), e.Message)
+	if len(e.Metadata) > 0 {
+		ke = ke.WithMetadata(e.Metadata)
+	}
+	return ke
+}
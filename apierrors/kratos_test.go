@@ -0,0 +1,89 @@
+package apierrors_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+// Error 模拟 kratos/v2/errors.Error, 测试中以本包代替 kratos 错误包
+type Error struct {
+	Code     int32
+	Reason   string
+	Message  string
+	Metadata map[string]string
+}
+
+func (e *Error) Error() string                  { return e.Message }
+func (e *Error) GetCode() int32                 { return e.Code }
+func (e *Error) GetReason() string              { return e.Reason }
+func (e *Error) GetMessage() string             { return e.Message }
+func (e *Error) GetMetadata() map[string]string { return e.Metadata }
+
+func (e *Error) WithMetadata(md map[string]string) *Error {
+	return &Error{Code: e.Code, Reason: e.Reason, Message: e.Message, Metadata: md}
+}
+
+// statusError 与 kratos 错误方法集相同的其他错误
+type statusError struct{ code int32 }
+
+func (e *statusError) Error() string                  { return "status" }
+func (e *statusError) GetCode() int32                 { return e.code }
+func (e *statusError) GetReason() string              { return "KRATOS_LOOKALIKE" }
+func (e *statusError) GetMessage() string             { return "lookalike" }
+func (e *statusError) GetMetadata() map[string]string { return nil }
+
+func newKratos(code int, reason, message string) *Error {
+	return &Error{Code: int32(code), Reason: reason, Message: message}
+}
+
+func useTestKratos(t *testing.T) {
+	t.Helper()
+	old := *apierrors.KratosErrorsPath
+	*apierrors.KratosErrorsPath = reflect.TypeOf(Error{}).PkgPath()
+	t.Cleanup(func() { *apierrors.KratosErrorsPath = old })
+}
+
+func TestFromKratos(t *testing.T) {
+	useTestKratos(t)
+	registered := apierrors.New(404, "kratos.v1.KRATOS_USER_NOT_FOUND", "user not found", "no such user")
+	apierrors.Register(registered)
+
+	e := apierrors.FromError(fmt.Errorf("wrap: %w", &Error{Code: 404, Reason: "KRATOS_USER_NOT_FOUND", Message: "user 7 not found", Metadata: map[string]string{"id": "7"}}))
+	if !errors.Is(e, registered) || e.Message != "user 7 not found" || e.Metadata["id"] != "7" || e.Pretty != "no such user" {
+		t.Errorf("FromError(kratos) = %v, want registered error", e)
+	}
+	e = apierrors.FromError(&Error{Code: 409, Reason: "KRATOS_CONFLICT", Message: "conflict"})
+	if e.Code != 409 || e.Reason != "KRATOS_CONFLICT" {
+		t.Errorf("FromError(unregistered kratos) = %v", e)
+	}
+}
+
+func TestFromKratosIgnoresLookalikes(t *testing.T) {
+	useTestKratos(t)
+	e := apierrors.FromError(&statusError{code: 404})
+	if e.Code != apierrors.UnknownCode || e.Reason != apierrors.UnknownReason {
+		t.Errorf("FromError(lookalike) = %v, want unknown error", e)
+	}
+}
+
+func TestLookupIgnoresShortReason(t *testing.T) {
+	apierrors.Register(apierrors.New(404, "kratos.v1.KRATOS_ONLY_FULL", "", ""))
+	if e, ok := apierrors.Lookup("KRATOS_ONLY_FULL"); ok {
+		t.Errorf("Lookup by enum value name = %v, want not found", e)
+	}
+}
+
+func TestToKratos(t *testing.T) {
+	e := apierrors.New(404, "kratos.v1.KRATOS_ITEM_NOT_FOUND", "item not found", "").WithMetadata(map[string]string{"id": "7"})
+	ke := apierrors.ToKratos(e, newKratos)
+	if ke.Code != 404 || ke.Reason != "KRATOS_ITEM_NOT_FOUND" || ke.Message != "item not found" || ke.Metadata["id"] != "7" {
+		t.Errorf("ToKratos = %+v", ke)
+	}
+	if apierrors.ToKratos(nil, newKratos) != nil {
+		t.Errorf("ToKratos(nil) should be nil")
+	}
+}
@@ -0,0 +1,96 @@
+// Package messaging
+//
+//	异步消息(Kafka/NATS 等)中 apierrors.Error 的消息头编码,及消费失败时 ack/重试/死信的决策
+package messaging
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+// 消息头 key
+const (
+	HeaderReason   = "error-reason"
+	HeaderCode     = "error-code"
+	HeaderMessage  = "error-message"
+	HeaderPretty   = "error-pretty"
+	HeaderMetadata = "error-metadata"
+)
+
+// Carrier 消息头的读写接口, 与 otel 的 propagation.TextMapCarrier 兼容
+type Carrier interface {
+	// Get returns the value associated with the passed key.
+	Get(key string) string
+	// Set stores the key-value pair.
+	Set(key string, value string)
+}
+
+// MapCarrier 使用 map 的 Carrier
+type MapCarrier map[string]string
+
+// Get implements Carrier.
+func (c MapCarrier) Get(key string) string {
+	return c[key]
+}
+
+// Set implements Carrier.
+func (c MapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Inject 将 err 编码到消息头, err 为 nil 时不做处理
+//
+//	Message、Pretty 及 Metadata 经 URL 编码,仅含 ASCII 字符
+func Inject(err error, c Carrier) {
+	if err == nil {
+		return
+	}
+	e := apierrors.FromError(err)
+	c.Set(HeaderReason, e.Reason)
+	c.Set(HeaderCode, strconv.Itoa(int(e.Code)))
+	if e.Message != "" {
+		c.Set(HeaderMessage, url.QueryEscape(e.Message))
+	}
+	if e.Pretty != "" {
+		c.Set(HeaderPretty, url.QueryEscape(e.Pretty))
+	}
+	if len(e.Metadata) > 0 {
+		md := url.Values{}
+		for k, v := range e.Metadata {
+			md.Set(k, v)
+		}
+		c.Set(HeaderMetadata, md.Encode())
+	}
+}
+
+// Extract 从消息头还原错误, 消息头不含错误时返回 nil
+//
+//	reason 已注册时返回注册的错误并覆盖 metadata, 消息头含 message 时一并覆盖
+func Extract(c Carrier) *apierrors.Error {
+	reason := c.Get(HeaderReason)
+	rawCode := c.Get(HeaderCode)
+	if reason == "" && rawCode == "" {
+		return nil
+	}
+	md := map[string]string{}
+	if values, err := url.ParseQuery(c.Get(HeaderMetadata)); err == nil {
+		for k := range values {
+			md[k] = values.Get(k)
+		}
+	}
+	message, _ := url.QueryUnescape(c.Get(HeaderMessage))
+	if e, ok := apierrors.Lookup(reason); ok {
+		if message != "" {
+			e = e.WithMessage(message)
+		}
+		return e.WithMetadata(md)
+	}
+	code, err := strconv.Atoi(rawCode)
+	if err != nil {
+		code = apierrors.UnknownCode
+	}
+	pretty, _ := url.QueryUnescape(c.Get(HeaderPretty))
+	return apierrors.New(code, reason, message, pretty).WithMetadata(md)
+}
@@ -0,0 +1,83 @@
+package messaging
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+var quotaExceeded = apierrors.New(429, "messaging.v1.QUOTA_EXCEEDED", "quota exceeded", "额度不足")
+
+func init() {
+	apierrors.Register(quotaExceeded)
+}
+
+func TestInjectExtract(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *apierrors.Error
+	}{
+		{
+			name: "registered",
+			err:  quotaExceeded.WithMetadata(map[string]string{"user": "1"}),
+			want: quotaExceeded.WithMetadata(map[string]string{"user": "1"}),
+		},
+		// 发送方改写的 message 随消息头传递
+		{
+			name: "registered message",
+			err:  quotaExceeded.WithMessage("quota exceeded: 100/100"),
+			want: quotaExceeded.WithMessage("quota exceeded: 100/100"),
+		},
+		{
+			name: "unregistered",
+			err:  apierrors.New(409, "messaging.v1.CONFLICT", "订单已支付 100%", "请勿重复支付").WithMetadata(map[string]string{"订单": "a&b=c"}),
+			want: apierrors.New(409, "messaging.v1.CONFLICT", "订单已支付 100%", "请勿重复支付").WithMetadata(map[string]string{"订单": "a&b=c"}),
+		},
+		{
+			name: "plain",
+			err:  errors.New("dial tcp: connection refused\nretry"),
+			want: apierrors.New(apierrors.UnknownCode, apierrors.UnknownReason, "dial tcp: connection refused\nretry", ""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := MapCarrier{}
+			Inject(tt.err, c)
+			for k, v := range c {
+				for i := 0; i < len(v); i++ {
+					if v[i] < ' ' || v[i] > '~' {
+						t.Fatalf("header %s = %q contains non-printable ASCII", k, v)
+					}
+				}
+			}
+			got := Extract(c)
+			if got == nil {
+				t.Fatal("Extract = nil")
+			}
+			if got.Code != tt.want.Code || got.Reason != tt.want.Reason || got.Message != tt.want.Message || got.Pretty != tt.want.Pretty {
+				t.Errorf("Extract = %+v, want %+v", &got.Status, &tt.want.Status)
+			}
+			if len(got.Metadata) != len(tt.want.Metadata) {
+				t.Errorf("Metadata = %v, want %v", got.Metadata, tt.want.Metadata)
+			}
+			for k, v := range tt.want.Metadata {
+				if got.Metadata[k] != v {
+					t.Errorf("Metadata[%s] = %q, want %q", k, got.Metadata[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractEmpty(t *testing.T) {
+	c := MapCarrier{}
+	Inject(nil, c)
+	if len(c) != 0 {
+		t.Errorf("Inject(nil) set %v", c)
+	}
+	if e := Extract(c); e != nil {
+		t.Errorf("Extract = %v, want nil", e)
+	}
+}
@@ -0,0 +1,113 @@
+package messaging
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+// Action 消费失败后的处理方式
+type Action int
+
+const (
+	// Ack 确认消息,不再投递
+	Ack Action = iota
+	// Retry 延迟后重新投递
+	Retry
+	// DeadLetter 投递到死信队列
+	DeadLetter
+)
+
+func (a Action) String() string {
+	switch a {
+	case Ack:
+		return "ack"
+	case Retry:
+		return "retry"
+	case DeadLetter:
+		return "dead_letter"
+	}
+	return "unknown"
+}
+
+// Decision 决策结果
+type Decision struct {
+	Action Action
+	// Delay Action 为 Retry 时重新投递前的延迟
+	Delay time.Duration
+}
+
+// Rule 处理规则
+type Rule struct {
+	Action Action
+	// Delay 首次重试的延迟,之后每次翻倍
+	Delay time.Duration
+	// MaxDelay 重试延迟的上限, 0 表示不限制
+	MaxDelay time.Duration
+	// MaxAttempts 最大投递次数, 超过后转为 DeadLetter, 0 表示不限制
+	MaxAttempts int
+}
+
+// Policy 按 reason 或 code 配置的处理策略
+//
+//	依次匹配 Reasons、Codes, 均未匹配时 4xx 使用 ClientError, 其余使用 ServerError
+type Policy struct {
+	Reasons     map[string]Rule
+	Codes       map[int]Rule
+	ClientError Rule
+	ServerError Rule
+}
+
+// DefaultPolicy 默认策略: 4xx 进入死信队列, 其余最多投递 5 次, 重试延迟从 1s 开始翻倍, 最长 1min
+var DefaultPolicy = &Policy{
+	ClientError: Rule{Action: DeadLetter},
+	ServerError: Rule{Action: Retry, Delay: time.Second, MaxDelay: time.Minute, MaxAttempts: 5},
+}
+
+// Decide 根据消费返回的 err 及已投递次数 attempt(从 1 开始) 决策, err 为 nil 时返回 Ack
+func (p *Policy) Decide(err error, attempt int) Decision {
+	if err == nil {
+		return Decision{Action: Ack}
+	}
+	return p.rule(apierrors.FromError(err)).decide(attempt)
+}
+
+func (p *Policy) rule(e *apierrors.Error) Rule {
+	if r, ok := p.Reasons[e.Reason]; ok {
+		return r
+	}
+	if r, ok := p.Codes[int(e.Code)]; ok {
+		return r
+	}
+	if e.Code >= http.StatusBadRequest && e.Code < http.StatusInternalServerError {
+		return p.ClientError
+	}
+	return p.ServerError
+}
+
+func (r Rule) decide(attempt int) Decision {
+	if r.Action != Retry {
+		return Decision{Action: r.Action}
+	}
+	if r.MaxAttempts > 0 && attempt >= r.MaxAttempts {
+		return Decision{Action: DeadLetter}
+	}
+	delay := r.Delay
+	for i := 1; i < attempt && delay > 0 && delay <= math.MaxInt64/2; i++ {
+		if r.MaxDelay > 0 && delay >= r.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if r.MaxDelay > 0 && delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	return Decision{Action: Retry, Delay: delay}
+}
+
+// Decide 使用 DefaultPolicy 决策
+func Decide(err error, attempt int) Decision {
+	return DefaultPolicy.Decide(err, attempt)
+}
@@ -0,0 +1,116 @@
+// Package metrics
+//
+//	按 code/reason/severity/operation/direction 统计错误数的 prometheus 指标
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DirectionServer 服务端返回的错误
+	DirectionServer = "server"
+	// DirectionClient 客户端收到的错误
+	DirectionClient = "client"
+	// Unregistered 未注册的 reason 统一使用的 label 值,避免 label 基数失控
+	Unregistered = "unregistered"
+)
+
+// Option 指标选项
+type Option func(*options)
+
+type options struct {
+	namespace  string
+	subsystem  string
+	registerer prometheus.Registerer
+	route      func(r *http.Request) string
+}
+
+// WithNamespace 设置指标 namespace
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithSubsystem 设置指标 subsystem
+func WithSubsystem(subsystem string) Option {
+	return func(o *options) {
+		o.subsystem = subsystem
+	}
+}
+
+// WithRegisterer 设置注册指标的 Registerer,默认 prometheus.DefaultRegisterer
+func WithRegisterer(r prometheus.Registerer) Option {
+	return func(o *options) {
+		o.registerer = r
+	}
+}
+
+// WithRoute 设置 http 请求的 operation label 取值,默认 r.URL.Path
+//
+//	带路径参数的路由应返回路由模板(如 /users/{id}),避免 label 基数失控
+func WithRoute(route func(r *http.Request) string) Option {
+	return func(o *options) {
+		o.route = route
+	}
+}
+
+// Metrics 错误计数器
+type Metrics struct {
+	errors *prometheus.CounterVec
+	route  func(r *http.Request) string
+}
+
+// New 创建并注册错误计数器 errors_total
+//
+//	同一 Registerer 重复创建时复用已注册的计数器
+func New(opts ...Option) *Metrics {
+	o := &options{
+		registerer: prometheus.DefaultRegisterer,
+		route: func(r *http.Request) string {
+			return r.URL.Path
+		},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: o.namespace,
+		Subsystem: o.subsystem,
+		Name:      "errors_total",
+		Help:      "Total number of errors by code, reason, severity, operation and direction.",
+	}, []string{"code", "reason", "severity", "operation", "direction"})
+	if err := o.registerer.Register(counter); err != nil {
+		are := prometheus.AlreadyRegisteredError{}
+		if !errors.As(err, &are) {
+			panic(err)
+		}
+		counter = are.ExistingCollector.(*prometheus.CounterVec)
+	}
+	return &Metrics{errors: counter, route: o.route}
+}
+
+// Observe 记录一次错误,err 为 nil 时忽略
+//
+//	operation 为 grpc 方法名或 http 路由, direction 为 DirectionServer 或 DirectionClient
+func (m *Metrics) Observe(err error, operation, direction string) {
+	if err == nil {
+		return
+	}
+	e := apierrors.FromError(err)
+	m.errors.WithLabelValues(strconv.Itoa(int(e.Code)), ReasonLabel(e.Reason), e.Severity().String(), operation, direction).Inc()
+}
+
+// ReasonLabel 返回 reason 对应的 label 值,仅已注册的 reason 原样使用,其余返回 Unregistered
+func ReasonLabel(reason string) string {
+	if _, ok := apierrors.Lookup(reason); ok {
+		return reason
+	}
+	return Unregistered
+}
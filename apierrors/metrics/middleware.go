@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/alkaid/goerrors/apierrors"
+	apihttp "github.com/alkaid/goerrors/apierrors/http"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor 统计服务端一元调用返回的错误
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		m.Observe(err, info.FullMethod, DirectionServer)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 统计服务端流调用返回的错误
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		m.Observe(err, info.FullMethod, DirectionServer)
+		return err
+	}
+}
+
+// UnaryClientInterceptor 统计客户端一元调用收到的错误
+func (m *Metrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.Observe(err, method, DirectionClient)
+		return err
+	}
+}
+
+// Middleware 返回 http 中间件,统计 apihttp.WriteError 写出的错误
+func (m *Metrics) Middleware() func(http.Handler) http.Handler {
+	return apihttp.Observe(func(r *http.Request, e *apierrors.Error) {
+		m.Observe(e, m.route(r), DirectionServer)
+	})
+}
+
+// maxErrorBodySize RoundTripper 解析错误响应时读取 body 的上限, 超出时仅统计状态码
+const maxErrorBodySize = 4 << 10
+
+// RoundTripper 返回统计 http 客户端收到的错误的 http.RoundTripper,next 为 nil 时使用 http.DefaultTransport
+//
+//	仅 Content-Type 为 JSON(application/json、application/problem+json 等)时读取至多 4KB body,
+//	由 apihttp.ErrorFromResponse 解析出 reason, 读取的部分会重放给调用方; 其余响应不读取 body, 仅统计状态码
+func (m *Metrics) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(r)
+		if err != nil {
+			m.Observe(err, m.route(r), DirectionClient)
+			return resp, err
+		}
+		if e := errorFromResponse(resp); e != nil {
+			m.Observe(e, m.route(r), DirectionClient)
+		}
+		return resp, nil
+	})
+}
+
+// errorFromResponse 从 resp 中解析错误, 状态码小于 400 时返回 nil
+func errorFromResponse(resp *http.Response) *apierrors.Error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	if resp.Body == nil || !isJSON(resp.Header.Get("Content-Type")) {
+		return apierrors.New(resp.StatusCode, apierrors.UnknownReason, "", "")
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = replayBody{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	head := *resp
+	head.Body = io.NopCloser(bytes.NewReader(body))
+	return apihttp.ErrorFromResponse(&head)
+}
+
+// isJSON 判断 contentType 是否为 JSON 或 +json 后缀的媒体类型
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// replayBody 重放已读取的部分,关闭时关闭原 body
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
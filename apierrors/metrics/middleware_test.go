@@ -0,0 +1,115 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+	apihttp "github.com/alkaid/goerrors/apierrors/http"
+	"github.com/alkaid/goerrors/apierrors/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var quotaExceeded = apierrors.New(429, "metrics.v1.QUOTA_EXCEEDED", "quota exceeded", "")
+
+func init() {
+	apierrors.Register(quotaExceeded)
+}
+
+// countingBody 记录被读取的字节数
+type countingBody struct {
+	io.Reader
+	n int
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.n += n
+	return n, err
+}
+
+func (b *countingBody) Close() error { return nil }
+
+// observed 返回 reg 中 errors_total 唯一一条序列的 label
+func observed(t *testing.T, reg *prometheus.Registry) map[string]string {
+	t.Helper()
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mfs) != 1 || len(mfs[0].GetMetric()) != 1 {
+		t.Fatalf("got %d metric families, want 1 series", len(mfs))
+	}
+	labels := map[string]string{}
+	for _, l := range mfs[0].GetMetric()[0].GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	return labels
+}
+
+func TestRoundTripper(t *testing.T) {
+	b, err := apihttp.MarshalStatus(quotaExceeded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := string(b)
+	large := strings.Repeat("x", 1<<20)
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantReason  string
+		// wantRead 为 RoundTripper 最多读取的字节数
+		wantRead int
+	}{
+		{name: "json", contentType: "application/json", body: status, wantReason: quotaExceeded.Reason, wantRead: len(status)},
+		{name: "json charset", contentType: "application/json; charset=utf-8", body: status, wantReason: quotaExceeded.Reason, wantRead: len(status)},
+		// 非 JSON 响应不读取 body
+		{name: "html", contentType: "text/html", body: large, wantReason: metrics.Unregistered, wantRead: 0},
+		{name: "no content type", body: large, wantReason: metrics.Unregistered, wantRead: 0},
+		// 超大 JSON 响应至多读取 4KB, 无法解析时仅统计状态码
+		{name: "large json", contentType: "application/json", body: large, wantReason: metrics.Unregistered, wantRead: 4 << 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			m := metrics.New(metrics.WithRegisterer(reg))
+			body := &countingBody{Reader: strings.NewReader(tt.body)}
+			next := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				header := http.Header{}
+				if tt.contentType != "" {
+					header.Set("Content-Type", tt.contentType)
+				}
+				return &http.Response{StatusCode: 429, Header: header, Body: body, Request: r}, nil
+			})
+			resp, err := m.RoundTripper(next).RoundTrip(httptest.NewRequest(http.MethodGet, "/quota", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body.n > tt.wantRead {
+				t.Errorf("RoundTripper read %d bytes, want at most %d", body.n, tt.wantRead)
+			}
+			labels := observed(t, reg)
+			if labels["code"] != "429" || labels["reason"] != tt.wantReason || labels["direction"] != metrics.DirectionClient {
+				t.Errorf("labels = %v, want code 429, reason %q", labels, tt.wantReason)
+			}
+			// 调用方仍可读取完整的 body
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.body {
+				t.Errorf("body length = %d, want %d", len(got), len(tt.body))
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
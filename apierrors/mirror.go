@@ -0,0 +1,44 @@
+package apierrors
+
+import "strconv"
+
+// Mirror 将错误的 reason、code、错误ID及白名单内的 metadata 镜像到响应头或 grpc trailer 的配置
+//
+//	供 envoy、nginx 等无法解析 grpc-status-details-bin 的代理记录访问日志. 头名称为空时不输出.
+//	grpc metadata 及 http 头的值只能是可打印 ASCII, 因此值按 grpc-message 的规则百分号编码:
+//	可打印 ASCII 以外的字节及 % 编码为 %XX, 可用 url.PathUnescape 还原
+type Mirror struct {
+	ReasonHeader  string
+	CodeHeader    string
+	ErrorIDHeader string
+	// MetadataHeaders metadata key 到头名称的白名单, 未列出的 metadata 不会输出
+	MetadataHeaders map[string]string
+}
+
+// DefaultMirror 默认配置, 输出 x-error-reason、x-error-code 及 x-error-id
+var DefaultMirror = &Mirror{
+	ReasonHeader:  "x-error-reason",
+	CodeHeader:    "x-error-code",
+	ErrorIDHeader: "x-error-id",
+}
+
+// Headers 返回 err 需要输出的头, err 为 nil 时返回 nil
+func (m *Mirror) Headers(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+	e := FromError(err)
+	headers := make(map[string]string)
+	set := func(name, value string) {
+		if name != "" && value != "" {
+			headers[name] = encodeGRPCMessage(value)
+		}
+	}
+	set(m.ReasonHeader, e.Reason)
+	set(m.CodeHeader, strconv.Itoa(int(e.Code)))
+	set(m.ErrorIDHeader, e.ErrorID())
+	for k, name := range m.MetadataHeaders {
+		set(name, e.Metadata[k])
+	}
+	return headers
+}
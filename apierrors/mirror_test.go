@@ -0,0 +1,46 @@
+package apierrors_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+func TestMirrorHeaders(t *testing.T) {
+	m := &apierrors.Mirror{
+		ReasonHeader:    "x-error-reason",
+		MetadataHeaders: map[string]string{"user": "x-error-user"},
+	}
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "ascii", value: "alice smith", want: "alice smith"},
+		{name: "non-ascii", value: "张三", want: "%E5%BC%A0%E4%B8%89"},
+		{name: "percent", value: "100%", want: "100%25"},
+		{name: "control", value: "a\nb", want: "a%0Ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := apierrors.New(404, "mirror.v1.NOT_FOUND", "", "").WithMetadata(map[string]string{"user": tt.value})
+			headers := m.Headers(e)
+			got := headers["x-error-user"]
+			if got != tt.want {
+				t.Errorf("header = %q, want %q", got, tt.want)
+			}
+			for i := 0; i < len(got); i++ {
+				if got[i] < ' ' || got[i] > '~' {
+					t.Fatalf("header %q contains non-printable ASCII", got)
+				}
+			}
+			if v, err := url.PathUnescape(got); err != nil || v != tt.value {
+				t.Errorf("PathUnescape(%q) = %q, %v, want %q", got, v, err, tt.value)
+			}
+			if headers["x-error-reason"] != "mirror.v1.NOT_FOUND" {
+				t.Errorf("reason header = %q", headers["x-error-reason"])
+			}
+		})
+	}
+}
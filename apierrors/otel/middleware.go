@@ -0,0 +1,51 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alkaid/goerrors/apierrors"
+	apihttp "github.com/alkaid/goerrors/apierrors/http"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor 将 handler 返回的错误记录到当前 span
+//
+//	需在创建 span 的拦截器(如 otelgrpc)之后执行
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		o.record(ctx, trace.SpanFromContext(ctx), err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 将 handler 返回的错误记录到当前 span
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		o.record(ss.Context(), trace.SpanFromContext(ss.Context()), err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor 将调用返回的错误记录到当前 span
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		o.record(ctx, trace.SpanFromContext(ctx), err)
+		return err
+	}
+}
+
+// Middleware 返回 http 中间件,将 apihttp.WriteError 写出的错误记录到当前 span
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts)
+	return apihttp.Observe(func(r *http.Request, e *apierrors.Error) {
+		o.record(r.Context(), trace.SpanFromContext(r.Context()), e)
+	})
+}
@@ -0,0 +1,158 @@
+// Package otel
+//
+//	将 apierrors.Error 记录到 OpenTelemetry span
+package otel
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/alkaid/goerrors/apierrors"
+	pkgerrors "github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// CodeKey 错误码属性
+	CodeKey = attribute.Key("error.code")
+	// ReasonKey 错误原因属性
+	ReasonKey = attribute.Key("error.reason")
+	// SeverityKey 错误严重程度属性
+	SeverityKey = attribute.Key("error.severity")
+	// MetadataKeyPrefix metadata 属性前缀,如 error.metadata.user_id
+	MetadataKeyPrefix = "error.metadata."
+)
+
+// Filter 决定 e 是否作为错误记录
+//
+//	返回 true 时设置 span status 为 Error 并添加 exception 事件;
+//	返回 false 时仅添加 error.code/error.reason 等属性,不会触发基于 span status 的告警
+type Filter func(ctx context.Context, e *apierrors.Error) bool
+
+// SampleReasons 按 reason 采样的 Filter
+//
+//	rates[reason] 为作为错误记录的概率,取值 [0,1];未配置的 reason 总是记录
+func SampleReasons(rates map[string]float64) Filter {
+	return func(_ context.Context, e *apierrors.Error) bool {
+		rate, ok := rates[e.Reason]
+		if !ok {
+			return true
+		}
+		return rate > 0 && (rate >= 1 || rand.Float64() < rate)
+	}
+}
+
+// SkipReasons 不将指定 reason 作为错误记录的 Filter,如 NotFound 等预期内的错误
+func SkipReasons(reasons ...string) Filter {
+	rates := make(map[string]float64, len(reasons))
+	for _, r := range reasons {
+		rates[r] = 0
+	}
+	return SampleReasons(rates)
+}
+
+// SkipExpected 不将预期内的错误(见 apierrors.Expected)作为错误记录的 Filter
+func SkipExpected() Filter {
+	return func(_ context.Context, e *apierrors.Error) bool {
+		return !e.Expected()
+	}
+}
+
+// Option 记录选项
+type Option func(*options)
+
+type options struct {
+	filter       Filter
+	metadataKeys []string
+}
+
+// WithFilter 设置 Filter,默认全部作为错误记录
+func WithFilter(f Filter) Option {
+	return func(o *options) {
+		o.filter = f
+	}
+}
+
+// WithMetadataKeys 设置需要记录为属性的 metadata key,默认不记录 metadata
+func WithMetadataKeys(keys ...string) Option {
+	return func(o *options) {
+		o.metadataKeys = keys
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// RecordError 将 err 记录到 ctx 中的活动 span
+func RecordError(ctx context.Context, err error, opts ...Option) {
+	newOptions(opts).record(ctx, trace.SpanFromContext(ctx), err)
+}
+
+// RecordSpan 将 err 记录到 span
+func RecordSpan(ctx context.Context, span trace.Span, err error, opts ...Option) {
+	newOptions(opts).record(ctx, span, err)
+}
+
+func (o *options) record(ctx context.Context, span trace.Span, err error) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+	e := apierrors.FromError(err)
+	attrs := []attribute.KeyValue{
+		CodeKey.Int(int(e.Code)),
+		ReasonKey.String(e.Reason),
+		SeverityKey.String(e.Severity().String()),
+	}
+	for _, k := range o.metadataKeys {
+		if v, ok := e.Metadata[k]; ok {
+			attrs = append(attrs, attribute.String(MetadataKeyPrefix+k, v))
+		}
+	}
+	span.SetAttributes(attrs...)
+	if o.filter != nil && !o.filter(ctx, e) {
+		return
+	}
+	span.SetStatus(codes.Error, e.Message)
+	eventAttrs := []attribute.KeyValue{
+		semconv.ExceptionTypeKey.String(exceptionType(e)),
+		semconv.ExceptionMessageKey.String(err.Error()),
+	}
+	if st := stackTrace(err); st != "" {
+		eventAttrs = append(eventAttrs, semconv.ExceptionStacktraceKey.String(st))
+	}
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(eventAttrs...))
+}
+
+func exceptionType(e *apierrors.Error) string {
+	if e.Reason != apierrors.UnknownReason {
+		return e.Reason
+	}
+	return fmt.Sprintf("%T", e)
+}
+
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// stackTrace 返回错误链中最内层的堆栈
+func stackTrace(err error) string {
+	var st stackTracer
+	for ; err != nil; err = apierrors.Unwrap(err) {
+		if s, ok := err.(stackTracer); ok { //nolint:errorlint // 需要遍历整条错误链
+			st = s
+		}
+	}
+	if st == nil {
+		return ""
+	}
+	return fmt.Sprintf("%+v", st.StackTrace())
+}
@@ -0,0 +1,124 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+func attrMap(attrs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(attrs))
+	for _, kv := range attrs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestRecordError(t *testing.T) {
+	notFound := apierrors.New(404, "user.v1.USER_NOT_FOUND", "user not found", "").
+		WithMetadata(map[string]string{"user_id": "42", "secret": "x"})
+	tests := []struct {
+		name       string
+		err        error
+		opts       []Option
+		wantStatus codes.Code
+		wantEvent  bool
+		wantStack  bool
+	}{
+		{
+			name:       "recorded with stack",
+			err:        notFound.WithStack(),
+			opts:       []Option{WithMetadataKeys("user_id")},
+			wantStatus: codes.Error,
+			wantEvent:  true,
+			wantStack:  true,
+		},
+		{
+			name:       "recorded without stack",
+			err:        notFound,
+			opts:       []Option{WithMetadataKeys("user_id")},
+			wantStatus: codes.Error,
+			wantEvent:  true,
+		},
+		{
+			name:       "filtered",
+			err:        notFound,
+			opts:       []Option{WithMetadataKeys("user_id"), WithFilter(SkipReasons("user.v1.USER_NOT_FOUND"))},
+			wantStatus: codes.Unset,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+			ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+			RecordError(ctx, tt.err, tt.opts...)
+			span.End()
+
+			spans := exp.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			s := spans[0]
+			attrs := attrMap(s.Attributes)
+			if got := attrs[CodeKey].AsInt64(); got != 404 {
+				t.Errorf("error.code = %d, want 404", got)
+			}
+			if got := attrs[ReasonKey].AsString(); got != "user.v1.USER_NOT_FOUND" {
+				t.Errorf("error.reason = %q", got)
+			}
+			if got := attrs[SeverityKey].AsString(); got != apierrors.Level_WARN.String() {
+				t.Errorf("error.severity = %q, want %q", got, apierrors.Level_WARN.String())
+			}
+			if got := attrs[MetadataKeyPrefix+"user_id"].AsString(); got != "42" {
+				t.Errorf("error.metadata.user_id = %q, want 42", got)
+			}
+			if _, ok := attrs[MetadataKeyPrefix+"secret"]; ok {
+				t.Errorf("unexpected error.metadata.secret")
+			}
+			if s.Status.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", s.Status.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == codes.Error && s.Status.Description != "user not found" {
+				t.Errorf("status description = %q", s.Status.Description)
+			}
+			if !tt.wantEvent {
+				if len(s.Events) != 0 {
+					t.Errorf("got %d events, want 0", len(s.Events))
+				}
+				return
+			}
+			if len(s.Events) != 1 || s.Events[0].Name != semconv.ExceptionEventName {
+				t.Fatalf("events = %+v, want one %s event", s.Events, semconv.ExceptionEventName)
+			}
+			ev := attrMap(s.Events[0].Attributes)
+			if got := ev[semconv.ExceptionTypeKey].AsString(); got != "user.v1.USER_NOT_FOUND" {
+				t.Errorf("exception.type = %q", got)
+			}
+			if got := ev[semconv.ExceptionMessageKey].AsString(); got != tt.err.Error() {
+				t.Errorf("exception.message = %q, want %q", got, tt.err.Error())
+			}
+			if _, ok := ev[semconv.ExceptionStacktraceKey]; ok != tt.wantStack {
+				t.Errorf("exception.stacktrace present = %v, want %v", ok, tt.wantStack)
+			}
+		})
+	}
+}
+
+func TestRecordErrorNil(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	RecordError(ctx, nil)
+	span.End()
+	s := exp.GetSpans()[0]
+	if s.Status.Code != codes.Unset || len(s.Attributes) != 0 || len(s.Events) != 0 {
+		t.Errorf("nil error recorded: status=%v attrs=%v events=%v", s.Status, s.Attributes, s.Events)
+	}
+}
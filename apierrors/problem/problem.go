@@ -0,0 +1,176 @@
+// Package problem
+//
+//	RFC 9457 Problem Details (application/problem+json) 编解码
+//	See: https://www.rfc-editor.org/rfc/rfc9457
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+const (
+	// ContentType problem details 的媒体类型
+	ContentType = "application/problem+json"
+	// BlankType 未指定 type 时的缺省值
+	BlankType = "about:blank"
+)
+
+// Problem RFC 9457 problem details 文档
+type Problem struct {
+	// Type 问题类型 URI,由 Codec.TypePrefix 与 reason 组成
+	Type string
+	// Title 问题的简短描述,取 Error.Pretty
+	Title string
+	// Status http 状态码,取 Error.Code
+	Status int
+	// Detail 问题的具体描述,取 Error.Message
+	Detail string
+	// Instance 问题发生的具体位置,如请求路径
+	Instance string
+	// Extensions 扩展成员,取 Error.Metadata
+	Extensions map[string]any
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5) //nolint:gomnd // 标准成员数
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*p = Problem{Extensions: map[string]any{}}
+	for k, v := range m {
+		switch k {
+		case "type":
+			p.Type, _ = v.(string)
+		case "title":
+			p.Title, _ = v.(string)
+		case "status":
+			if f, ok := v.(float64); ok {
+				p.Status = int(f)
+			}
+		case "detail":
+			p.Detail, _ = v.(string)
+		case "instance":
+			p.Instance, _ = v.(string)
+		default:
+			p.Extensions[k] = v
+		}
+	}
+	return nil
+}
+
+// Codec *apierrors.Error 与 Problem 之间的转换
+type Codec struct {
+	// TypePrefix type 的 URI 前缀,如 "https://errors.example.com/", type 为 TypePrefix + Reason
+	TypePrefix string
+}
+
+// DefaultCodec 默认转换器
+var DefaultCodec = &Codec{}
+
+// Encode 将 err 转换为 Problem
+//
+//	reason 为空时 type 为 about:blank; Pretty 为空时 title 使用状态码的标准描述;
+//	status 与响应的状态码相同, Code 不是合法的 http 状态码时为 500; 与标准成员同名的 metadata 会被忽略
+func (c *Codec) Encode(err error, instance string) *Problem {
+	e := apierrors.FromError(err)
+	p := &Problem{
+		Type:       BlankType,
+		Title:      e.Pretty,
+		Status:     statusCode(e),
+		Detail:     e.Message,
+		Instance:   instance,
+		Extensions: make(map[string]any, len(e.Metadata)),
+	}
+	if e.Reason != apierrors.UnknownReason {
+		p.Type = c.TypePrefix + e.Reason
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	for k, v := range e.Metadata {
+		if !isMember(k) {
+			p.Extensions[k] = v
+		}
+	}
+	return p
+}
+
+// Decode 将 Problem 转换为 *apierrors.Error
+//
+//	reason 已注册时返回注册的错误并覆盖 message 及 metadata;
+//	type 不以 TypePrefix 开头时不是本 Codec 输出的类型, reason 为空;
+//	仅字符串类型的扩展成员会还原为 metadata
+func (c *Codec) Decode(p *Problem) *apierrors.Error {
+	reason := apierrors.UnknownReason
+	if p.Type != "" && p.Type != BlankType && strings.HasPrefix(p.Type, c.TypePrefix) {
+		reason = p.Type[len(c.TypePrefix):]
+	}
+	md := make(map[string]string, len(p.Extensions))
+	for k, v := range p.Extensions {
+		if s, ok := v.(string); ok {
+			md[k] = s
+		}
+	}
+	if e, ok := apierrors.Lookup(reason); ok {
+		return e.WithMessage(p.Detail).WithMetadata(md)
+	}
+	code := p.Status
+	if code == 0 {
+		code = apierrors.UnknownCode
+	}
+	pretty := p.Title
+	if pretty == http.StatusText(code) {
+		pretty = ""
+	}
+	return apierrors.New(code, reason, p.Detail, pretty).WithMetadata(md)
+}
+
+// Encode 使用 DefaultCodec 将 err 转换为 Problem
+func Encode(err error, instance string) *Problem {
+	return DefaultCodec.Encode(err, instance)
+}
+
+// Decode 使用 DefaultCodec 将 Problem 转换为 *apierrors.Error
+func Decode(p *Problem) *apierrors.Error {
+	return DefaultCodec.Decode(p)
+}
+
+// statusCode 返回 e 对应的 http 状态码, 规则同 apierrors/http.StatusCode
+func statusCode(e *apierrors.Error) int {
+	code := int(e.Code)
+	if code < 100 || code > 599 {
+		return http.StatusInternalServerError
+	}
+	return code
+}
+
+func isMember(k string) bool {
+	switch k {
+	case "type", "title", "status", "detail", "instance":
+		return true
+	}
+	return false
+}
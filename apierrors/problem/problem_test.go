@@ -0,0 +1,101 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+)
+
+func TestRoundTrip(t *testing.T) {
+	registered := apierrors.New(404, "problem.v1.PROBLEM_NOT_FOUND", "not found", "item not found")
+	apierrors.Register(registered)
+	codec := &Codec{TypePrefix: "https://errors.example.com/"}
+	tests := []struct {
+		name     string
+		err      error
+		wantType string
+		wantCode int32
+		// wantIs 还原结果应匹配的错误, 为 nil 时不检查
+		wantIs *apierrors.Error
+	}{
+		{
+			name:     "registered",
+			err:      registered.WithMessage("item 7 not found").WithMetadata(map[string]string{"id": "7", "status": "ignored"}),
+			wantType: "https://errors.example.com/problem.v1.PROBLEM_NOT_FOUND",
+			wantCode: 404,
+			wantIs:   registered,
+		},
+		{
+			name:     "unregistered",
+			err:      apierrors.New(409, "problem.v1.CONFLICT", "conflict", ""),
+			wantType: "https://errors.example.com/problem.v1.CONFLICT",
+			wantCode: 409,
+		},
+		{
+			name:     "blank type",
+			err:      errors.New("boom"),
+			wantType: BlankType,
+			wantCode: 500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := apierrors.FromError(tt.err)
+			p := codec.Encode(tt.err, "/items/7")
+			if p.Type != tt.wantType || p.Status != int(tt.wantCode) || p.Instance != "/items/7" {
+				t.Errorf("Encode = %+v", p)
+			}
+			data, err := json.Marshal(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := &Problem{}
+			if err := json.Unmarshal(data, decoded); err != nil {
+				t.Fatal(err)
+			}
+			got := codec.Decode(decoded)
+			if got.Code != tt.wantCode || got.Reason != want.Reason || got.Message != want.Message || got.Pretty != want.Pretty {
+				t.Errorf("round trip of %s = %v, want %v", data, got, want)
+			}
+			if got.Metadata["id"] != want.Metadata["id"] {
+				t.Errorf("metadata = %v, want %v", got.Metadata, want.Metadata)
+			}
+			if tt.wantIs != nil && !errors.Is(got, tt.wantIs) {
+				t.Errorf("decoded error does not match registered error")
+			}
+		})
+	}
+}
+
+func TestEncodeInvalidCode(t *testing.T) {
+	for _, code := range []int{0, 99, 600, 700} {
+		p := Encode(apierrors.New(code, "problem.v1.ODD", "", ""), "")
+		if p.Status != 500 || p.Title != "Internal Server Error" {
+			t.Errorf("Encode(code %d) status, title = %d, %q, want 500", code, p.Status, p.Title)
+		}
+	}
+}
+
+func TestDecodeForeignType(t *testing.T) {
+	codec := &Codec{TypePrefix: "https://errors.example.com/"}
+	tests := []struct {
+		typ  string
+		want string
+	}{
+		{typ: "https://errors.example.com/problem.v1.BAD", want: "problem.v1.BAD"},
+		{typ: "https://other.example.com/out-of-credit", want: ""},
+		{typ: BlankType, want: ""},
+		{typ: "", want: ""},
+	}
+	for _, tt := range tests {
+		e := codec.Decode(&Problem{Type: tt.typ, Status: 403, Title: "Forbidden"})
+		if e.Reason != tt.want || e.Code != 403 || e.Pretty != "" {
+			t.Errorf("Decode(type %q) = %v (pretty %q), want reason %q", tt.typ, e, e.Pretty, tt.want)
+		}
+	}
+	if e := Decode(&Problem{Type: "out-of-credit"}); e.Reason != "out-of-credit" || e.Code != 500 {
+		t.Errorf("Decode without TypePrefix = %v, want type as reason", e)
+	}
+}
@@ -0,0 +1,60 @@
+package apierrors
+
+import "net/http"
+
+// WithSeverity set severity to current Error
+//
+//	仅在本进程内有效,不会随错误传输;客户端可通过注册表还原
+func (e *Error) WithSeverity(severity Level) *Error {
+	err := Clone(e)
+	err.severity = severity
+	return err
+}
+
+// WithExpected 标记为预期内的错误,如 USER_NOT_FOUND
+//
+//	仅在本进程内有效,不会随错误传输;客户端可通过注册表还原
+func (e *Error) WithExpected(expected bool) *Error {
+	err := Clone(e)
+	err.expected = expected
+	return err
+}
+
+// Severity returns the severity of current Error.
+//
+//	未声明时: 预期内的错误为 INFO, 5xx 为 ERROR, 其余为 WARN
+func (e *Error) Severity() Level {
+	switch {
+	case e.severity != Level_LEVEL_UNSPECIFIED:
+		return e.severity
+	case e.expected:
+		return Level_INFO
+	case e.Code >= http.StatusInternalServerError:
+		return Level_ERROR
+	default:
+		return Level_WARN
+	}
+}
+
+// Expected reports whether current Error is expected.
+func (e *Error) Expected() bool {
+	return e.expected
+}
+
+// Severity returns the severity for a particular error.
+// It supports wrapped errors.
+func Severity(err error) Level {
+	if err == nil {
+		return Level_LEVEL_UNSPECIFIED
+	}
+	return FromError(err).Severity()
+}
+
+// Expected reports whether a particular error is expected.
+// It supports wrapped errors.
+func Expected(err error) bool {
+	if err == nil {
+		return false
+	}
+	return FromError(err).Expected()
+}
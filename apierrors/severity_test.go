@@ -0,0 +1,45 @@
+package apierrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Level
+	}{
+		{name: "nil", want: Level_LEVEL_UNSPECIFIED},
+		// 未声明时按 expected 及 code 推导
+		{name: "4xx", err: New(400, "severity.v1.INVALID", "", ""), want: Level_WARN},
+		{name: "5xx", err: New(503, "severity.v1.UNAVAILABLE", "", ""), want: Level_ERROR},
+		{name: "expected", err: New(404, "severity.v1.NOT_FOUND", "", "").WithExpected(true), want: Level_INFO},
+		{name: "expected 5xx", err: New(503, "severity.v1.UNAVAILABLE", "", "").WithExpected(true), want: Level_INFO},
+		// 显式声明优先
+		{name: "explicit", err: New(404, "severity.v1.NOT_FOUND", "", "").WithExpected(true).WithSeverity(Level_DEBUG), want: Level_DEBUG},
+		{name: "critical", err: New(400, "severity.v1.INVALID", "", "").WithSeverity(Level_CRITICAL), want: Level_CRITICAL},
+		{name: "wrapped", err: fmt.Errorf("wrap: %w", New(400, "severity.v1.INVALID", "", "").WithSeverity(Level_CRITICAL)), want: Level_CRITICAL},
+		{name: "plain", err: errors.New("boom"), want: Level_ERROR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Severity(tt.err); got != tt.want {
+				t.Errorf("Severity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithSeverityCopies(t *testing.T) {
+	e := New(404, "severity.v1.NOT_FOUND", "", "")
+	_ = e.WithSeverity(Level_CRITICAL).WithExpected(true)
+	if e.Severity() != Level_WARN || e.Expected() {
+		t.Errorf("WithSeverity/WithExpected modified the original error: %v, %v", e.Severity(), e.Expected())
+	}
+	if !Expected(fmt.Errorf("wrap: %w", e.WithExpected(true))) || Expected(nil) {
+		t.Errorf("Expected should unwrap and be false for nil")
+	}
+}
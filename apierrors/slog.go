@@ -0,0 +1,65 @@
+//go:build go1.21
+
+// slog 在 Go 1.21 加入标准库, 本文件仅在 Go 1.21 及以上编译, go.mod 仍声明 go 1.19 以兼容旧版本
+
+package apierrors
+
+import (
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer.
+//
+//	输出 code/reason/message/severity/pretty/biz_code/error_id/metadata/cause, 便于日志与客户端看到的错误按 error_id 关联
+func (e *Error) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Int("code", int(e.Code)),
+		slog.String("reason", e.Reason),
+		slog.String("message", e.Message),
+		slog.String("severity", e.Severity().String()),
+	}
+	if e.Pretty != "" {
+		attrs = append(attrs, slog.String("pretty", e.Pretty))
+	}
+	if e.BizCode != 0 {
+		attrs = append(attrs, slog.Int(MetadataBizCode, int(e.BizCode)))
+	}
+	if id := e.ErrorID(); id != "" {
+		attrs = append(attrs, slog.String(MetadataErrorID, id))
+	}
+	if len(e.Metadata) > 0 {
+		md := make([]any, 0, len(e.Metadata))
+		for k, v := range e.Metadata {
+			md = append(md, slog.String(k, v))
+		}
+		attrs = append(attrs, slog.Group("metadata", md...))
+	}
+	if e.cause != nil {
+		attrs = append(attrs, slog.String("cause", e.cause.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// SlogLevel 返回严重程度对应的 slog.Level, CRITICAL 高于 slog.LevelError
+func (x Level) SlogLevel() slog.Level {
+	switch x {
+	case Level_DEBUG:
+		return slog.LevelDebug
+	case Level_INFO:
+		return slog.LevelInfo
+	case Level_WARN:
+		return slog.LevelWarn
+	case Level_CRITICAL:
+		return slog.LevelError + 4 //nolint:gomnd // slog 各级别间隔为 4
+	default:
+		return slog.LevelError
+	}
+}
+
+// LogLevel 返回 err 对应的 slog.Level, err 为 nil 时返回 slog.LevelInfo
+func LogLevel(err error) slog.Level {
+	if err == nil {
+		return slog.LevelInfo
+	}
+	return Severity(err).SlogLevel()
+}
@@ -0,0 +1,31 @@
+//go:build go1.21
+
+package apierrors
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want slog.Level
+	}{
+		{name: "nil", want: slog.LevelInfo},
+		{name: "debug", err: New(400, "slog.v1.INVALID", "", "").WithSeverity(Level_DEBUG), want: slog.LevelDebug},
+		{name: "expected", err: New(404, "slog.v1.NOT_FOUND", "", "").WithExpected(true), want: slog.LevelInfo},
+		{name: "4xx", err: New(400, "slog.v1.INVALID", "", ""), want: slog.LevelWarn},
+		{name: "5xx", err: New(500, "slog.v1.INTERNAL", "", ""), want: slog.LevelError},
+		// CRITICAL 高于 slog.LevelError
+		{name: "critical", err: New(500, "slog.v1.INTERNAL", "", "").WithSeverity(Level_CRITICAL), want: slog.LevelError + 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LogLevel(tt.err); got != tt.want {
+				t.Errorf("LogLevel = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
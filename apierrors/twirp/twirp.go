@@ -0,0 +1,174 @@
+// Package twirp
+//
+//	apierrors 与 twirp.Error 之间的转换.
+//	导入本包后 apierrors.FromError 可识别 twirp.Error 并经注册表还原
+package twirp
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"github.com/alkaid/goerrors/apierrors/http/status"
+	"github.com/twitchtv/twirp"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// MetaReason 携带 Error.Reason 的 twirp meta key
+	MetaReason = "reason"
+	// MetaPretty 携带 Error.Pretty 的 twirp meta key
+	MetaPretty = "pretty"
+	// MetaPrefix Error.Metadata 放入 meta 时 key 的前缀, 避免与 MetaReason 等保留 key 冲突
+	MetaPrefix = "md."
+)
+
+func init() {
+	apierrors.RegisterConverter(func(err error) (*apierrors.Error, bool) {
+		var te twirp.Error
+		if errors.As(err, &te) {
+			return FromTwirp(te), true
+		}
+		return nil, false
+	})
+}
+
+var toTwirpCodes = map[codes.Code]twirp.ErrorCode{
+	codes.Canceled:           twirp.Canceled,
+	codes.Unknown:            twirp.Unknown,
+	codes.InvalidArgument:    twirp.InvalidArgument,
+	codes.DeadlineExceeded:   twirp.DeadlineExceeded,
+	codes.NotFound:           twirp.NotFound,
+	codes.AlreadyExists:      twirp.AlreadyExists,
+	codes.PermissionDenied:   twirp.PermissionDenied,
+	codes.ResourceExhausted:  twirp.ResourceExhausted,
+	codes.FailedPrecondition: twirp.FailedPrecondition,
+	codes.Aborted:            twirp.Aborted,
+	codes.OutOfRange:         twirp.OutOfRange,
+	codes.Unimplemented:      twirp.Unimplemented,
+	codes.Internal:           twirp.Internal,
+	codes.Unavailable:        twirp.Unavailable,
+	codes.DataLoss:           twirp.DataLoss,
+	codes.Unauthenticated:    twirp.Unauthenticated,
+}
+
+var fromTwirpCodes = func() map[twirp.ErrorCode]codes.Code {
+	m := map[twirp.ErrorCode]codes.Code{
+		twirp.Malformed: codes.InvalidArgument,
+		twirp.BadRoute:  codes.Unimplemented,
+	}
+	for c, tc := range toTwirpCodes {
+		m[tc] = c
+	}
+	return m
+}()
+
+// ToTwirpCode converts an HTTP error code into the corresponding twirp error code.
+func ToTwirpCode(code int) twirp.ErrorCode {
+	if tc, ok := toTwirpCodes[status.ToGRPCCode(code)]; ok {
+		return tc
+	}
+	return twirp.Unknown
+}
+
+// FromTwirpCode converts a twirp error code into the corresponding HTTP error code.
+func FromTwirpCode(code twirp.ErrorCode) int {
+	c, ok := fromTwirpCodes[code]
+	if !ok {
+		c = codes.Unknown
+	}
+	return status.FromGRPCCode(c)
+}
+
+// ToTwirp 将 err 转换为 twirp.Error, err 为 nil 时返回 nil
+//
+//	err 本身为 twirp.Error 时原样返回;
+//	非空的 Reason 及 Pretty 分别放在 meta 的 MetaReason 及 MetaPretty 中, Metadata 的 key 加上 MetaPrefix 后放入 meta
+func ToTwirp(err error) twirp.Error {
+	if err == nil {
+		return nil
+	}
+	if te, ok := err.(twirp.Error); ok { //nolint:errorlint // 仅原样返回未包装的 twirp.Error
+		return te
+	}
+	e := apierrors.FromError(err)
+	tc, ok := toTwirpCodes[e.GRPCCode()]
+	if !ok {
+		tc = twirp.Unknown
+	}
+	te := twirp.NewError(tc, e.Message)
+	for k, v := range e.Metadata {
+		te = te.WithMeta(MetaPrefix+k, v)
+	}
+	if e.Reason != "" {
+		te = te.WithMeta(MetaReason, e.Reason)
+	}
+	if e.Pretty != "" {
+		te = te.WithMeta(MetaPretty, e.Pretty)
+	}
+	return twirp.WrapError(te, err)
+}
+
+// FromTwirp 将 twirp.Error 转换为 *apierrors.Error
+//
+//	reason 已注册时返回注册的错误并覆盖 message 及 metadata;
+//	以 MetaPrefix 开头的 meta 去掉前缀后作为 metadata, 其余非保留的 meta(如非本库的服务端设置的)原样作为 metadata, 同名时前者优先
+func FromTwirp(te twirp.Error) *apierrors.Error {
+	meta := te.MetaMap()
+	md := make(map[string]string, len(meta))
+	for k, v := range meta {
+		if k != MetaReason && k != MetaPretty && !strings.HasPrefix(k, MetaPrefix) {
+			md[k] = v
+		}
+	}
+	for k, v := range meta {
+		if strings.HasPrefix(k, MetaPrefix) {
+			md[k[len(MetaPrefix):]] = v
+		}
+	}
+	reason := te.Meta(MetaReason)
+	if e, ok := apierrors.Lookup(reason); ok {
+		return e.WithMessage(te.Msg()).WithMetadata(md)
+	}
+	return apierrors.New(FromTwirpCode(te.Code()), reason, te.Msg(), te.Meta(MetaPretty)).WithMetadata(md).WithCause(te)
+}
+
+// ServerInterceptor 服务端拦截器, 将 handler 返回的错误经 ToTwirp 转换
+//
+//	未经转换的非 twirp.Error 会被 twirp 视为 Internal
+func ServerInterceptor() twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req any) (any, error) {
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, ToTwirp(err)
+			}
+			return resp, nil
+		}
+	}
+}
+
+// ClientInterceptor 客户端拦截器, 将收到的 twirp.Error 经 FromTwirp 转换为 *apierrors.Error
+func ClientInterceptor() twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req any) (any, error) {
+			resp, err := next(ctx, req)
+			var te twirp.Error
+			if errors.As(err, &te) {
+				return resp, FromTwirp(te)
+			}
+			return resp, err
+		}
+	}
+}
+
+// ServerHooks 返回在错误发生时以 *apierrors.Error 回调 fn 的 twirp.ServerHooks, 用于日志、metrics 等
+func ServerHooks(fn func(ctx context.Context, e *apierrors.Error)) *twirp.ServerHooks {
+	return &twirp.ServerHooks{
+		Error: func(ctx context.Context, te twirp.Error) context.Context {
+			fn(ctx, FromTwirp(te))
+			return ctx
+		},
+	}
+}
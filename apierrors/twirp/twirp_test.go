@@ -0,0 +1,98 @@
+package twirp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"github.com/twitchtv/twirp"
+)
+
+func TestRoundTrip(t *testing.T) {
+	registered := apierrors.New(404, "twirp.v1.TWIRP_NOT_FOUND", "not found", "no such item")
+	apierrors.Register(registered)
+	tests := []struct {
+		name     string
+		err      error
+		wantCode twirp.ErrorCode
+		// wantIs 还原结果应匹配的错误, 为 nil 时不检查
+		wantIs *apierrors.Error
+	}{
+		{
+			name:     "registered",
+			err:      registered.WithMessage("item 7 not found").WithMetadata(map[string]string{"id": "7"}),
+			wantCode: twirp.NotFound,
+			wantIs:   registered,
+		},
+		{
+			name:     "reserved metadata keys",
+			err:      apierrors.New(409, "twirp.v1.CONFLICT", "conflict", "try again").WithMetadata(map[string]string{"reason": "mine", "pretty": "also mine"}),
+			wantCode: twirp.Aborted,
+		},
+		{
+			name:     "empty reason",
+			err:      errors.New("boom"),
+			wantCode: twirp.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := apierrors.FromError(tt.err)
+			te := ToTwirp(tt.err)
+			if te.Code() != tt.wantCode {
+				t.Errorf("code = %v, want %v", te.Code(), tt.wantCode)
+			}
+			if _, ok := te.MetaMap()[MetaReason]; ok != (want.Reason != "") {
+				t.Errorf("meta = %v, want reason only when not empty", te.MetaMap())
+			}
+			got := FromTwirp(te)
+			if got.Reason != want.Reason || got.Message != want.Message || got.Pretty != want.Pretty {
+				t.Errorf("round trip = %v, want %v", got, want)
+			}
+			if len(got.Metadata) != len(want.Metadata) {
+				t.Errorf("metadata = %v, want %v", got.Metadata, want.Metadata)
+			}
+			for k, v := range want.Metadata {
+				if got.Metadata[k] != v {
+					t.Errorf("metadata[%q] = %q, want %q", k, got.Metadata[k], v)
+				}
+			}
+			if tt.wantIs != nil && !errors.Is(got, tt.wantIs) {
+				t.Errorf("decoded error does not match registered error")
+			}
+		})
+	}
+}
+
+func TestFromTwirpForeignMeta(t *testing.T) {
+	te := twirp.NewError(twirp.InvalidArgument, "bad").WithMeta("argument", "name")
+	e := FromTwirp(te)
+	if e.Code != 400 || e.Metadata["argument"] != "name" {
+		t.Errorf("FromTwirp = %v, want meta kept as metadata", e)
+	}
+}
+
+func TestConverterRegistered(t *testing.T) {
+	te := twirp.NewError(twirp.NotFound, "gone").WithMeta(MetaReason, "twirp.v1.GONE")
+	e := apierrors.FromError(fmt.Errorf("call: %w", te))
+	if e.Code != 404 || e.Reason != "twirp.v1.GONE" || e.Message != "gone" {
+		t.Errorf("FromError(twirp) = %v", e)
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	e := apierrors.New(403, "twirp.v1.DENIED", "denied", "")
+	server := ServerInterceptor()(func(ctx context.Context, req any) (any, error) { return nil, e })
+	_, err := server(context.Background(), nil)
+	var te twirp.Error
+	if !errors.As(err, &te) || te.Code() != twirp.PermissionDenied {
+		t.Fatalf("server error = %v, want twirp.Error", err)
+	}
+	client := ClientInterceptor()(func(ctx context.Context, req any) (any, error) { return nil, te })
+	_, err = client(context.Background(), nil)
+	if got := apierrors.FromError(err); got.Reason != "twirp.v1.DENIED" || got.Code != 403 {
+		t.Errorf("client error = %v", err)
+	}
+}
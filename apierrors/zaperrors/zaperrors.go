@@ -0,0 +1,82 @@
+// Package zaperrors
+//
+//	将 apierrors.Error 以结构化字段输出到 zap 日志
+package zaperrors
+
+import (
+	"github.com/alkaid/goerrors/apierrors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Error 返回 key 为 "error" 的 zap.Field
+//
+//	err 会经 apierrors.FromError 转换,输出 code/reason/message/severity/pretty/error_id/metadata/cause
+func Error(err error) zap.Field {
+	return NamedError("error", err)
+}
+
+// NamedError 返回指定 key 的 zap.Field
+func NamedError(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, Object{apierrors.FromError(err)})
+}
+
+// Object 实现 zapcore.ObjectMarshaler
+type Object struct {
+	*apierrors.Error
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (o Object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	e := o.Error
+	enc.AddInt32("code", e.Code)
+	enc.AddString("reason", e.Reason)
+	enc.AddString("message", e.Message)
+	enc.AddString("severity", e.Severity().String())
+	if e.Pretty != "" {
+		enc.AddString("pretty", e.Pretty)
+	}
+	if id := e.ErrorID(); id != "" {
+		enc.AddString(apierrors.MetadataErrorID, id)
+	}
+	if len(e.Metadata) > 0 {
+		if err := enc.AddObject("metadata", metadata(e.Metadata)); err != nil {
+			return err
+		}
+	}
+	if cause := e.Cause(); cause != nil {
+		enc.AddString("cause", cause.Error())
+	}
+	return nil
+}
+
+// Level 返回 err 严重程度对应的 zapcore.Level, err 为 nil 时返回 zapcore.InfoLevel
+//
+//	CRITICAL 对应 zapcore.ErrorLevel 而非 DPanicLevel, 以免开发模式的 logger panic
+func Level(err error) zapcore.Level {
+	if err == nil {
+		return zapcore.InfoLevel
+	}
+	switch apierrors.Severity(err) {
+	case apierrors.Level_DEBUG:
+		return zapcore.DebugLevel
+	case apierrors.Level_INFO:
+		return zapcore.InfoLevel
+	case apierrors.Level_WARN:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+type metadata map[string]string
+
+func (m metadata) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for k, v := range m {
+		enc.AddString(k, v)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package zaperrors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want zapcore.Level
+	}{
+		{name: "nil", want: zapcore.InfoLevel},
+		{name: "debug", err: apierrors.New(400, "zap.v1.INVALID", "", "").WithSeverity(apierrors.Level_DEBUG), want: zapcore.DebugLevel},
+		{name: "expected", err: apierrors.New(404, "zap.v1.NOT_FOUND", "", "").WithExpected(true), want: zapcore.InfoLevel},
+		{name: "4xx", err: apierrors.New(400, "zap.v1.INVALID", "", ""), want: zapcore.WarnLevel},
+		{name: "5xx", err: apierrors.New(500, "zap.v1.INTERNAL", "", ""), want: zapcore.ErrorLevel},
+		{name: "plain", err: errors.New("boom"), want: zapcore.ErrorLevel},
+		// CRITICAL 对应 ErrorLevel 而非 DPanicLevel, 开发模式的 logger 不会 panic
+		{name: "critical", err: apierrors.New(500, "zap.v1.INTERNAL", "", "").WithSeverity(apierrors.Level_CRITICAL), want: zapcore.ErrorLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Level(tt.err); got != tt.want {
+				t.Errorf("Level = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalLogObject(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	e := apierrors.New(404, "zap.v1.NOT_FOUND", "not found", "").WithSeverity(apierrors.Level_CRITICAL)
+	if err := (Object{e}).MarshalLogObject(enc); err != nil {
+		t.Fatal(err)
+	}
+	if enc.Fields["severity"] != "CRITICAL" || enc.Fields["reason"] != "zap.v1.NOT_FOUND" || enc.Fields["code"] != int32(404) {
+		t.Errorf("fields = %v", enc.Fields)
+	}
+}
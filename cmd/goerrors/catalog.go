@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// catalog 错误目录, 字段与 errors.proto 中的选项一一对应
+type catalog struct {
+	// GoPackage 生成代码所在的 Go 包, 格式同 proto 的 go_package, 如 github.com/foo/user/errs;errs
+	GoPackage string `yaml:"go_package"`
+	// Package reason 的前缀, 同 proto 包名, 如 user.v1
+	Package string `yaml:"package"`
+	// Domain 缺省 domain, 同文件选项 default_domain
+	Domain string         `yaml:"domain"`
+	Enums  []*catalogEnum `yaml:"enums"`
+}
+
+// catalogEnum 错误枚举
+type catalogEnum struct {
+	Name    string `yaml:"name"`
+	Comment string `yaml:"comment"`
+	// DefaultCode 同枚举选项 default_code
+	DefaultCode int32 `yaml:"default_code"`
+	// DefaultGRPCCode 同枚举选项 default_grpc_code, 取值为 RPCCode 的名称, 如 NOT_FOUND
+	DefaultGRPCCode string `yaml:"default_grpc_code"`
+	// Domain 同枚举选项 domain
+	Domain string          `yaml:"domain"`
+	Errors []*catalogError `yaml:"errors"`
+	// pos 在目录中的位置, 格式同插件诊断的 file:line:column
+	pos string
+}
+
+// catalogError 单个错误, 即枚举值
+type catalogError struct {
+	Name string `yaml:"name"`
+	// Number 枚举值编号, 未声明时为前一个编号加 1, 首个为 0
+	Number  *int32 `yaml:"number"`
+	Comment string `yaml:"comment"`
+	// Code 同枚举值选项 code, 为 0 时只生成枚举值而不生成错误
+	Code int32 `yaml:"code"`
+	// GRPCCode 同枚举值选项 grpc_code
+	GRPCCode string `yaml:"grpc_code"`
+	Message  string `yaml:"message"`
+	Pretty   string `yaml:"pretty"`
+	// Severity 同枚举值选项 severity, 取值为 Level 的名称, 如 INFO
+	Severity string `yaml:"severity"`
+	Expected bool   `yaml:"expected"`
+	// BizCode 同枚举值选项 biz_code, 未声明时为枚举值编号
+	BizCode *int32 `yaml:"biz_code"`
+	// pos 在目录中的位置
+	pos string
+}
+
+// loadCatalog 读取 YAML 或 JSON 格式的错误目录, 未知字段视为错误
+func loadCatalog(path string) (*catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	c := &catalog{}
+	if err := dec.Decode(c); err != nil {
+		return nil, err
+	}
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(data, root); err != nil {
+		return nil, err
+	}
+	c.setPositions(path, root)
+	return c, nil
+}
+
+// setPositions 按 YAML 节点记录各枚举及错误在目录中的位置, 无法确定时为文件名
+func (c *catalog) setPositions(path string, root *yaml.Node) {
+	pos := func(n *yaml.Node) string {
+		return fmt.Sprintf("%s:%d:%d", path, n.Line, n.Column)
+	}
+	for _, enum := range c.Enums {
+		enum.pos = path
+		for _, v := range enum.Errors {
+			v.pos = path
+		}
+	}
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	enums := mappingValue(root, "enums")
+	if enums == nil || len(enums.Content) != len(c.Enums) {
+		return
+	}
+	for i, n := range enums.Content {
+		enum := c.Enums[i]
+		enum.pos = pos(n)
+		errs := mappingValue(n, "errors")
+		if errs == nil || len(errs.Content) != len(enum.Errors) {
+			continue
+		}
+		for j, vn := range errs.Content {
+			enum.Errors[j].pos = pos(vn)
+		}
+	}
+}
+
+// mappingValue 返回 mapping 节点 n 中 key 对应的值, 不存在时返回 nil
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// goPackage 返回生成代码的导入路径及包名
+//
+//	包名优先取 go_package 中 ";" 后的部分, 其次为 go generate 设置的 $GOPACKAGE, 最后为导入路径的最后一段
+func (c *catalog) goPackage() (importPath, name string) {
+	importPath, name, _ = strings.Cut(c.GoPackage, ";")
+	if name == "" {
+		name = os.Getenv("GOPACKAGE")
+	}
+	if name == "" && importPath != "" {
+		name = importPath[strings.LastIndex(importPath, "/")+1:]
+	}
+	return importPath, name
+}
+
+// protoComment 将目录中的注释转为与 protogen.Comments.String() 相同的格式, 每行以 // 开头
+func protoComment(comment string) string {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(comment, "\n") {
+		b.WriteString("// " + strings.TrimSpace(line) + "\n")
+	}
+	return b.String()
+}
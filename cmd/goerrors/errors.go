@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"strconv"
+
+	"github.com/alkaid/goerrors/apierrors"
+	"github.com/alkaid/goerrors/apierrors/http/status"
+	"github.com/alkaid/goerrors/cmd/internal/errorsgen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// generate 按错误目录生成 Go 代码, 规则及诊断与 protoc-gen-go-errors 相同, 非 strict 模式下的 lint 问题作为警告输出到 w
+func generate(path, out string, c *catalog, w io.Writer) ([]byte, error) {
+	importPath, pkg := c.goPackage()
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("%s: invalid go package name %q, set go_package or run via go generate", path, pkg)
+	}
+	tmpl, err := errorsgen.LoadTemplate(*tmplPath)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{})
+	if err != nil {
+		return nil, err
+	}
+	g := gen.NewGeneratedFile(out, protogen.GoImportPath(importPath))
+	errorsgen.WriteHeader(g, "goerrors", protogen.GoPackageName(pkg))
+	errorsgen.WriteAssertion(g)
+	file := &errorsgen.FileInfo{
+		Path:          path,
+		Package:       c.Package,
+		GoPackageName: pkg,
+		GoImportPath:  importPath,
+	}
+	diags := &errorsgen.Diagnostics{Strict: *strict}
+	// 枚举值名与 proto 相同, 在包内唯一
+	names := map[string]string{}
+	for _, enum := range c.Enums {
+		ew := buildEnum(c, enum, names, diags)
+		if ew == nil {
+			continue
+		}
+		ew.File = file
+		genEnumType(g, enum)
+		if len(ew.Errors) == 0 {
+			continue
+		}
+		content, err := ew.Execute(tmpl, g)
+		if err != nil {
+			diags.Add(enumSite(c, enum), "execute template: %v", err)
+			continue
+		}
+		g.P(content)
+	}
+	diags.Warn(w)
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+	return g.Content()
+}
+
+// fullName 返回与 proto 相同的全名, 枚举值与枚举同级, 如 user.v1.USER_NOT_FOUND
+func fullName(c *catalog, name string) string {
+	if c.Package == "" {
+		return name
+	}
+	return c.Package + "." + name
+}
+
+// enumSite 返回 enum 的全名及在目录中的位置
+func enumSite(c *catalog, enum *catalogEnum) errorsgen.Site {
+	return errorsgen.Site{Name: fullName(c, enum.Name), Pos: enum.pos}
+}
+
+// grpcCodeOf 解析 RPCCode 名称, 为空时返回 codes.OK
+func grpcCodeOf(name string) (codes.Code, bool) {
+	if name == "" {
+		return codes.OK, true
+	}
+	c, ok := apierrors.RPCCode_value[name]
+	return codes.Code(c), ok
+}
+
+// buildEnum 返回 enum 的模板数据, enum 无效时返回 nil
+func buildEnum(c *catalog, enum *catalogEnum, names map[string]string, diags *errorsgen.Diagnostics) *errorsgen.ErrorWrapper {
+	es := enumSite(c, enum)
+	if !token.IsIdentifier(enum.Name) {
+		diags.Add(es, "enum '%s' name must be a valid Go identifier", es.Name)
+		return nil
+	}
+	code := int(enum.DefaultCode)
+	// 未声明 default_code 时由 default_grpc_code 转换
+	defaultGRPCCode, ok := grpcCodeOf(enum.DefaultGRPCCode)
+	if !ok {
+		diags.Add(es, "enum '%s' unknown default_grpc_code %q", es.Name, enum.DefaultGRPCCode)
+		return nil
+	}
+	if code == 0 && defaultGRPCCode != codes.OK {
+		code = status.FromGRPCCode(defaultGRPCCode)
+	}
+	if !diags.CheckDefaultCode(es, code) {
+		return nil
+	}
+	domain := enum.Domain
+	if domain == "" {
+		domain = c.Domain
+	}
+	ew := &errorsgen.ErrorWrapper{
+		Enum:         enum.Name,
+		EnumName:     enum.Name,
+		EnumFullName: es.Name,
+		EnumComment:  protoComment(enum.Comment),
+		DefaultCode:  code,
+		Domain:       domain,
+		Constructors: *constructors,
+	}
+	// 未声明错误码而被跳过的错误, 作为 lint 问题报告
+	var skipped []errorsgen.Site
+	numbers := numbersOf(enum)
+	for i, v := range enum.Errors {
+		number := numbers[i]
+		key := fullName(c, v.Name)
+		vs := errorsgen.Site{Name: key, Pos: v.pos}
+		if !token.IsIdentifier(v.Name) {
+			diags.Add(vs, "enum value '%s' name must be a valid Go identifier", key)
+			continue
+		}
+		if other, ok := names[v.Name]; ok {
+			diags.Add(vs, "enum value '%s' name is already used in enum '%s'", key, other)
+			continue
+		}
+		names[v.Name] = enum.Name
+		enumCode := code
+		grpcCode := defaultGRPCCode
+		gc, ok := grpcCodeOf(v.GRPCCode)
+		if !ok {
+			diags.Add(vs, "enum value '%s' unknown grpc_code %q", key, v.GRPCCode)
+			continue
+		}
+		if gc != codes.OK {
+			grpcCode = gc
+			enumCode = status.FromGRPCCode(gc)
+		}
+		if v.Code != 0 {
+			enumCode = int(v.Code)
+		}
+		if !diags.CheckCode(vs, enumCode) {
+			continue
+		}
+		enumValue := enum.Name + "_" + v.Name
+		if enumCode == 0 {
+			skipped = append(skipped, vs)
+			continue
+		}
+		upperCamelValue, lowerCamelValue := errorsgen.Names("", v.Name)
+		comment := errorsgen.Comment(upperCamelValue, protoComment(v.Comment))
+		msg := enumValue + ".String()"
+		if v.Message != "" {
+			msg = strconv.Quote(v.Message)
+		}
+		severity := ""
+		if v.Severity != "" {
+			if level, ok := apierrors.Level_value[v.Severity]; !ok {
+				diags.Add(vs, "enum value '%s' unknown severity %q", key, v.Severity)
+				continue
+			} else if apierrors.Level(level) != apierrors.Level_LEVEL_UNSPECIFIED {
+				severity = "Level_" + v.Severity
+			}
+		}
+		reason := key
+		if *shortReason {
+			reason = v.Name
+		}
+		// 业务错误码缺省为枚举值编号
+		bizCode := number
+		if v.BizCode != nil {
+			bizCode = *v.BizCode
+		}
+		grpcName := ""
+		if grpcCode != codes.OK {
+			grpcName = grpcCode.String()
+		}
+		e := &errorsgen.ErrorInfo{
+			Name:            enum.Name,
+			Value:           v.Name,
+			HTTPCode:        enumCode,
+			UpperCamelValue: upperCamelValue,
+			LowerCamelValue: lowerCamelValue,
+			Key:             key,
+			Reason:          reason,
+			Domain:          domain,
+			Comment:         comment,
+			HasComment:      len(comment) > 0,
+			Pretty:          v.Pretty,
+			Msg:             msg,
+			Severity:        severity,
+			Expected:        v.Expected,
+			BizCode:         bizCode,
+			GRPCCode:        grpcName,
+			EnumValue:       enumValue,
+			Message:         v.Message,
+			Number:          number,
+		}
+		diags.CheckError(ew, e, vs, v.BizCode != nil)
+		ew.Add(e)
+	}
+	if len(ew.Errors) == 0 {
+		return ew
+	}
+	diags.LintEnumName(es)
+	for _, v := range enum.Errors {
+		diags.LintValueName(errorsgen.Site{Name: fullName(c, v.Name), Pos: v.pos})
+	}
+	for _, vs := range skipped {
+		diags.Skipped(vs)
+	}
+	return ew
+}
+
+// numbersOf 返回 enum 中各枚举值的编号, 未声明 number 时为前一个编号加 1, 首个为 0
+func numbersOf(enum *catalogEnum) []int32 {
+	numbers := make([]int32, len(enum.Errors))
+	number := int32(-1)
+	for i, v := range enum.Errors {
+		number++
+		if v.Number != nil {
+			number = *v.Number
+		}
+		numbers[i] = number
+	}
+	return numbers
+}
+
+// genEnumType 输出枚举类型, 与 protoc-gen-go 生成的枚举兼容(不含 protobuf 反射)
+func genEnumType(g *protogen.GeneratedFile, enum *catalogEnum) {
+	name := enum.Name
+	g.P(protoComment(enum.Comment), "type ", name, " int32")
+	g.P()
+	g.P("const (")
+	numbers := numbersOf(enum)
+	for i, v := range enum.Errors {
+		g.P(protoComment(v.Comment), name, "_", v.Name, " ", name, " = ", numbers[i])
+	}
+	g.P(")")
+	g.P()
+	g.P("// Enum value maps for ", name, ".")
+	g.P("var (")
+	g.P(name, "_name = map[int32]string{")
+	seen := map[int32]bool{}
+	for i, v := range enum.Errors {
+		if !seen[numbers[i]] {
+			g.P(numbers[i], ": ", strconv.Quote(v.Name), ",")
+		}
+		seen[numbers[i]] = true
+	}
+	g.P("}")
+	g.P(name, "_value = map[string]int32{")
+	for i, v := range enum.Errors {
+		g.P(strconv.Quote(v.Name), ": ", numbers[i], ",")
+	}
+	g.P("}")
+	g.P(")")
+	g.P()
+	g.P("func (x ", name, ") String() string {")
+	g.P("if s, ok := ", name, "_name[int32(x)]; ok {")
+	g.P("return s")
+	g.P("}")
+	g.P("return ", g.QualifiedGoIdent(protogen.GoImportPath("strconv").Ident("Itoa")), "(int(x))")
+	g.P("}")
+	g.P()
+}
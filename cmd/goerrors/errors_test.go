@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// generateCatalog 生成 YAML 错误目录 src, 返回生成的代码及警告, 目录路径为 errors.yaml
+func generateCatalog(t *testing.T, src string) (string, string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "errors.yaml"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	c, err := loadCatalog("errors.yaml")
+	if err != nil {
+		return "", "", err
+	}
+	warnings := &bytes.Buffer{}
+	content, err := generate("errors.yaml", "errors_gen.go", c, warnings)
+	return string(content), warnings.String(), err
+}
+
+func TestAlias(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		wantCases []string
+		noCases   []string
+	}{
+		{
+			name: "alias of skipped value is not an alias",
+			src: `go_package: example.com/a;a
+enums:
+  - name: ErrorReason
+    errors:
+      - name: UNSPECIFIED
+      - name: ALIAS
+        number: 0
+        code: 404
+`,
+			wantCases: []string{"case ErrorReason_ALIAS:"},
+		},
+		{
+			name: "alias of generated value",
+			src: `go_package: example.com/a;a
+enums:
+  - name: ErrorReason
+    default_code: 404
+    errors:
+      - name: UNSPECIFIED
+        code: 500
+      - name: NOT_FOUND
+      - name: MISSING
+        number: 1
+`,
+			wantCases: []string{"case ErrorReason_UNSPECIFIED:", "case ErrorReason_NOT_FOUND:"},
+			noCases:   []string{"case ErrorReason_MISSING:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, _, err := generateCatalog(t, tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range tt.wantCases {
+				if !strings.Contains(content, c) {
+					t.Errorf("missing %q in:\n%s", c, content)
+				}
+			}
+			for _, c := range tt.noCases {
+				if strings.Contains(content, c) {
+					t.Errorf("unexpected %q in:\n%s", c, content)
+				}
+			}
+		})
+	}
+}
+
+func TestCatalogErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "unknown field",
+			src:  "enums:\n  - name: E\n    bogus: 1\n",
+			want: "field bogus not found",
+		},
+		{
+			name: "code out of range",
+			src:  "go_package: x;x\nenums:\n  - name: E\n    errors:\n      - name: A\n        code: 700\n",
+			want: "errors.yaml:5:9: enum value 'A' code 700 must be greater than 0",
+		},
+		{
+			name: "duplicate biz_code",
+			src:  "go_package: x;x\nenums:\n  - name: E\n    errors:\n      - name: A\n        code: 400\n        biz_code: 7\n      - name: B\n        code: 400\n        biz_code: 7\n",
+			want: "errors.yaml:8:9: enum value 'B' biz_code 7 is already used by 'A' at errors.yaml:5:9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := generateCatalog(t, tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBizCodeAcrossEnums(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		// want 错误应包含的内容, 为空时应生成成功
+		want string
+	}{
+		{
+			name: "default biz_code in several enums",
+			src: `go_package: example.com/a;a
+package: app.v1
+enums:
+  - name: UserReason
+    default_code: 404
+    errors:
+      - name: USER_UNSPECIFIED
+      - name: USER_NOT_FOUND
+  - name: OrderReason
+    default_code: 404
+    errors:
+      - name: ORDER_UNSPECIFIED
+      - name: ORDER_NOT_FOUND
+`,
+		},
+		{
+			name: "explicit biz_code in several enums",
+			src: `go_package: example.com/a;a
+package: app.v1
+enums:
+  - name: UserReason
+    default_code: 404
+    errors:
+      - name: USER_NOT_FOUND
+        biz_code: 40401
+  - name: OrderReason
+    default_code: 404
+    errors:
+      - name: ORDER_NOT_FOUND
+        biz_code: 40401
+`,
+			want: "errors.yaml:12:9: enum value 'app.v1.ORDER_NOT_FOUND' biz_code 40401 is already used by 'app.v1.USER_NOT_FOUND' at errors.yaml:7:9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := generateCatalog(t, tt.src)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogDiagnostics(t *testing.T) {
+	src := `go_package: example.com/a;a
+package: a
+enums:
+  - name: ErrorReason
+    default_code: 500
+    errors:
+      - name: NOT_FOUND
+        code: 404
+      - name: bad_name
+  - name: Mixed
+    errors:
+      - name: MIXED_UNSPECIFIED
+      - name: MIXED_CONFLICT
+        number: 2
+        code: 409
+        pretty: conflict
+`
+	// 与插件 TestDiagnostics 中的 lint 问题相同
+	wantLint := []string{
+		"errors.yaml:7:9: %senum value 'a.NOT_FOUND' with code 404 should declare pretty",
+		"errors.yaml:9:9: %senum value 'a.bad_name' name should be UPPER_SNAKE_CASE",
+		"errors.yaml:12:9: %senum value 'a.MIXED_UNSPECIFIED' is skipped because it declares no code",
+	}
+	t.Run("non-strict warns", func(t *testing.T) {
+		_, warnings, err := generateCatalog(t, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range wantLint {
+			if w = fmt.Sprintf(w, "warning: "); !strings.Contains(warnings, w) {
+				t.Errorf("warnings missing %q, got:\n%s", w, warnings)
+			}
+		}
+	})
+	t.Run("strict fails", func(t *testing.T) {
+		*strict = true
+		t.Cleanup(func() { *strict = false })
+		_, warnings, err := generateCatalog(t, src)
+		if warnings != "" {
+			t.Errorf("unexpected warnings in strict mode: %s", warnings)
+		}
+		for _, w := range wantLint {
+			if w = fmt.Sprintf(w, ""); err == nil || !strings.Contains(err.Error(), w) {
+				t.Errorf("error missing %q, got: %v", w, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,60 @@
+// goerrors 由 YAML/JSON 错误目录生成错误定义, 用于不使用 protobuf 的项目
+//
+//	生成的代码与 protoc-gen-go-errors 相同, 两者共用 cmd/internal/errorsgen 中的模型及模板.
+//	用法: //go:generate goerrors -o errors_gen.go errors.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var version string
+
+var (
+	showVersion  = flag.Bool("version", false, "print the version and exit")
+	output       = flag.String("o", "", "output file, defaults to the catalog name with suffix _errors.go")
+	shortReason  = flag.Bool("short_reason", false, "use the error name instead of the full name as reason, see AIP-193")
+	tmplPath     = flag.String("template", "", "custom template file, or directory containing errors.tmpl")
+	constructors = flag.Bool("constructors", false, "also generate NewXxx(cause, format, args...) constructors returning errors with stack")
+	strict       = flag.Bool("strict", false, "fail instead of warn on skipped errors, duplicate reasons, missing pretty for 4xx, quoted messages and naming violations")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: goerrors [flags] catalog.yaml\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *showVersion {
+		fmt.Printf("Version: %s\n", version)
+		return
+	}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, "goerrors:", err)
+		os.Exit(1)
+	}
+}
+
+func run(path string) error {
+	c, err := loadCatalog(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	out := *output
+	if out == "" {
+		out = strings.TrimSuffix(path, filepath.Ext(path)) + "_errors.go"
+	}
+	content, err := generate(path, out, c, os.Stderr)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, content, 0o644) //nolint:gosec
+}
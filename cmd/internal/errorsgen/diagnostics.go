@@ -0,0 +1,145 @@
+package errorsgen
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Site 错误定义在源文件中的位置
+type Site struct {
+	// Name 全名, 如 user.v1.USER_NOT_FOUND
+	Name string
+	// Pos 位置, 格式为 file:line:column, 无法确定行列时为文件名
+	Pos string
+}
+
+// Diagnostics 生成过程中发现的问题, 全部收集后一次性报告, protoc-gen-go-errors 与 goerrors 报告相同的问题
+//
+//	Lint 记录的问题在 Strict 模式下为错误, 否则为警告, 警告输出到 stderr 而不会使生成失败
+type Diagnostics struct {
+	Strict   bool
+	errors   []string
+	warnings []string
+	// bizCodes 本次生成中显式声明的业务错误码及对应的错误, 显式声明的业务错误码须全局唯一
+	bizCodes map[int32]Site
+	// reasons 本次生成中的 (domain, reason) 及对应的错误, 重复时报告 lint 问题
+	reasons map[string]Site
+}
+
+// Add 记录 s 处的错误
+func (d *Diagnostics) Add(s Site, format string, a ...any) {
+	d.errors = append(d.errors, s.Pos+": "+fmt.Sprintf(format, a...))
+}
+
+// Lint 记录 s 处的 lint 问题, Strict 模式下为错误, 否则为警告
+func (d *Diagnostics) Lint(s Site, format string, a ...any) {
+	if d.Strict {
+		d.Add(s, format, a...)
+		return
+	}
+	d.warnings = append(d.warnings, s.Pos+": warning: "+fmt.Sprintf(format, a...))
+}
+
+// Warn 将警告输出到 w
+func (d *Diagnostics) Warn(w io.Writer) {
+	for _, msg := range d.warnings {
+		fmt.Fprintln(w, msg)
+	}
+}
+
+// Err 返回合并后的错误, 无错误时返回 nil
+func (d *Diagnostics) Err() error {
+	if len(d.errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(d.errors, "\n"))
+}
+
+// CheckDefaultCode 检查枚举 s 的 default_code, 越界时返回 false
+func (d *Diagnostics) CheckDefaultCode(s Site, code int) bool {
+	if code > 600 || code < 0 {
+		d.Add(s, "enum '%s' default_code %d must be greater than 0 and less than or equal to 600", s.Name, code)
+		return false
+	}
+	return true
+}
+
+// CheckCode 检查枚举值 s 的 code, 越界时返回 false
+func (d *Diagnostics) CheckCode(s Site, code int) bool {
+	if code > 600 || code < 0 {
+		d.Add(s, "enum value '%s' code %d must be greater than 0 and less than or equal to 600", s.Name, code)
+		return false
+	}
+	return true
+}
+
+// Skipped 报告因未声明错误码而被跳过的枚举值 s
+func (d *Diagnostics) Skipped(s Site) {
+	d.Lint(s, "enum value '%s' is skipped because it declares no code", s.Name)
+}
+
+// CheckError 检查加入 ew 之前的错误 e
+//
+//	explicitBizCode 表示 e.BizCode 为显式声明: 显式声明的业务错误码须全局唯一, 缺省的枚举值编号只须在枚举内唯一,
+//	allow_alias 的别名与原错误是同一个错误, 不参与唯一性检查
+func (d *Diagnostics) CheckError(ew *ErrorWrapper, e *ErrorInfo, s Site, explicitBizCode bool) {
+	if strings.ContainsAny(e.Message, "\"\\\n") {
+		d.Lint(s, "enum value '%s' message %q contains quotes, backslashes or newlines", s.Name, e.Message)
+	}
+	if e.Pretty == "" && e.HTTPCode >= 400 && e.HTTPCode < 500 {
+		d.Lint(s, "enum value '%s' with code %d should declare pretty", s.Name, e.HTTPCode)
+	}
+	if d.reasons == nil {
+		d.reasons = map[string]Site{}
+	}
+	if other, ok := d.reasons[e.Domain+"/"+e.Reason]; ok {
+		d.Lint(s, "enum value '%s' reason %q is already used by '%s' at %s", s.Name, e.Reason, other.Name, other.Pos)
+	}
+	d.reasons[e.Domain+"/"+e.Reason] = s
+	if e.BizCode == 0 || ew.IsAlias(e.Number) {
+		return
+	}
+	if ew.bizCodes == nil {
+		ew.bizCodes = map[int32]Site{}
+	}
+	if d.bizCodes == nil {
+		d.bizCodes = map[int32]Site{}
+	}
+	other, ok := ew.bizCodes[e.BizCode]
+	if !ok && explicitBizCode {
+		other, ok = d.bizCodes[e.BizCode]
+	}
+	if ok {
+		d.Add(s, "enum value '%s' biz_code %d is already used by '%s' at %s", s.Name, e.BizCode, other.Name, other.Pos)
+	}
+	ew.bizCodes[e.BizCode] = s
+	if explicitBizCode {
+		d.bizCodes[e.BizCode] = s
+	}
+}
+
+var (
+	upperCamelCase = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	upperSnakeCase = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+)
+
+// LintEnumName 检查枚举名为 UpperCamelCase
+func (d *Diagnostics) LintEnumName(s Site) {
+	if !upperCamelCase.MatchString(shortName(s.Name)) {
+		d.Lint(s, "enum '%s' name should be UpperCamelCase", s.Name)
+	}
+}
+
+// LintValueName 检查枚举值名为 UPPER_SNAKE_CASE
+func (d *Diagnostics) LintValueName(s Site) {
+	if !upperSnakeCase.MatchString(shortName(s.Name)) {
+		d.Lint(s, "enum value '%s' name should be UPPER_SNAKE_CASE", s.Name)
+	}
+}
+
+// shortName 返回全名的最后一段
+func shortName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
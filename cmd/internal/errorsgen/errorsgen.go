@@ -0,0 +1,56 @@
+// Package errorsgen
+//
+//	protoc-gen-go-errors 与 goerrors 共用的错误模型、模板及命名规则, 保证两者生成的代码一致
+package errorsgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ErrorsPackage apierrors 的导入路径
+const ErrorsPackage = protogen.GoImportPath("github.com/alkaid/goerrors/apierrors")
+
+// WriteHeader 输出生成文件的头部注释及包声明
+func WriteHeader(g *protogen.GeneratedFile, generator string, pkg protogen.GoPackageName) {
+	g.P("// Code generated by ", generator, ". DO NOT EDIT.")
+	g.P()
+	g.P("package ", pkg)
+	g.P()
+}
+
+// WriteAssertion 输出与 apierrors 版本兼容的编译期断言
+func WriteAssertion(g *protogen.GeneratedFile) {
+	g.P("// This is a compile-time assertion to ensure that this generated file")
+	g.P("// is compatible with the kratos package it is being compiled against.")
+	g.P("const _ = ", ErrorsPackage.Ident("SupportPackageIsVersion1"))
+	g.P()
+}
+
+// Names 返回错误的访问函数名及变量名
+//
+//	value 经 strcase.ToCamel 转换, 全大写的单词保持原样, 如 NOT_FOUND 为 NOTFOUND, NotFound 仍为 NotFound.
+//	parent 为外层 message 的 Go 标识符, 非嵌套时为空; 嵌套时以 parent 限定,
+//	如 UserService 中的 NOT_FOUND 为 UserService_NOTFOUND 及 userService_NOTFOUND
+func Names(parent, value string) (upperCamel, lowerCamel string) {
+	upperCamel = strcase.ToCamel(value)
+	lowerCamel = strcase.ToLowerCamel(value)
+	if parent != "" {
+		lowerCamel = strcase.ToLowerCamel(parent) + "_" + upperCamel
+		upperCamel = parent + "_" + upperCamel
+	}
+	return upperCamel, lowerCamel
+}
+
+// Comment returns comment content with prefix //
+func Comment(upperCamelValue, comment string) string {
+	if comment == "" {
+		return ""
+	}
+
+	comment = strings.Replace(comment, "//", "", 1)
+	return fmt.Sprintf("// %s %s", upperCamelValue, comment)
+}
@@ -0,0 +1,241 @@
+package errorsgen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// DefaultTemplate 内置模板
+var DefaultTemplate = `
+{{ range .Errors }}
+var {{.LowerCamelValue}} *apierrors.Error
+{{- end }}
+
+func init() {
+{{- range .Errors }}
+{{.LowerCamelValue}} = apierrors.New({{.HTTPCode}}, {{printf "%q" .Reason}}, {{.Msg}}, {{printf "%q" .Pretty}})
+{{- if .Severity}}.WithSeverity(apierrors.{{.Severity}}){{end}}
+{{- if .Expected}}.WithExpected(true){{end}}
+{{- if .BizCode}}.WithBizCode({{.BizCode}}){{end}}
+{{- if .Domain}}.WithDomain({{printf "%q" .Domain}}){{end}}
+{{- if .GRPCCode}}.WithGRPCCode({{qualify "google.golang.org/grpc/codes" .GRPCCode}}){{end}}
+apierrors.Register({{.LowerCamelValue}})
+{{- end }}
+}
+
+{{ range .Errors }}
+{{if .HasComment}}{{.Comment}}{{end}}func {{.UpperCamelValue}}() *apierrors.Error {
+	 return {{.LowerCamelValue}}
+}
+{{- if $.Constructors}}
+
+// New{{.UpperCamelValue}} 返回带 cause 及堆栈的 {{.UpperCamelValue}} 错误, format 非空时按 fmt.Sprintf 追加到消息后
+func New{{.UpperCamelValue}}(cause error, format string, args ...any) error {
+	err := {{.LowerCamelValue}}.WithCause(cause)
+	if format != "" {
+		err = err.WithMessage(err.Message + ". " + {{qualify "fmt" "Sprintf"}}(format, args...))
+	}
+	return err.WithStackSkip(1)
+}
+{{- end}}
+{{ end }}
+// {{.Enum}}Of 返回 err 对应的 {{.Enum}} 枚举值, err 不是 {{.Enum}} 中的错误时返回 false
+//
+//	只比较错误链中最外层的 *apierrors.Error 的 reason 及 domain, 不会匹配到其 cause 中的错误
+func {{.Enum}}Of(err error) ({{.Enum}}, bool) {
+	if err == nil {
+		return 0, false
+	}
+	se := apierrors.FromError(err)
+	switch {
+{{- range .Errors }}
+	case se.Reason == {{.LowerCamelValue}}.Reason && se.Domain == {{.LowerCamelValue}}.Domain:
+		return {{.EnumValue}}, true
+{{- end }}
+	}
+	return 0, false
+}
+
+// Error 返回枚举值对应的错误, 不是错误的枚举值返回 nil
+func (x {{.Enum}}) Error() *apierrors.Error {
+	switch x {
+{{- range .Errors }}{{if not .Alias}}
+	case {{.EnumValue}}:
+		return {{.LowerCamelValue}}
+{{- end}}{{- end }}
+	}
+	return nil
+}
+`
+
+// ErrorInfo 单个错误的模板数据
+type ErrorInfo struct {
+	Name            string
+	Value           string
+	HTTPCode        int
+	CamelValue      string
+	UpperCamelValue string
+	LowerCamelValue string
+	Key             string
+	Reason          string
+	Domain          string
+	Comment         string
+	HasComment      bool
+	Pretty          string
+	// Msg message 的 Go 表达式, 已转义为字符串字面量或为 Enum_VALUE.String()
+	Msg string
+	// Message message 选项的原始值, 未声明时为空
+	Message  string
+	Severity string
+	Expected bool
+	BizCode  int32
+	// GRPCCode 声明的 grpc 状态码在 codes 包中的名称, 如 FailedPrecondition, 未声明时为空
+	GRPCCode string
+	// EnumValue 枚举值的 Go 标识符, 如 ErrorReason_USER_NOT_FOUND
+	EnumValue string
+	// Alias 是否与前面生成的错误编号相同(allow_alias), 由 ErrorWrapper.Add 设置
+	Alias bool
+	// Number 枚举值编号
+	Number int32
+	// Proto 枚举值的 protogen 描述, 可访问 Desc.Options() 等, 非 protobuf 来源时为 nil
+	Proto *protogen.EnumValue
+}
+
+// ErrorWrapper 单个错误枚举的模板数据, 模板按枚举逐个执行
+type ErrorWrapper struct {
+	// Enum 枚举的 Go 标识符
+	Enum string
+	// EnumName 枚举的 proto 名称及全名
+	EnumName     string
+	EnumFullName string
+	// EnumComment 枚举的前置注释
+	EnumComment string
+	// DefaultCode 枚举的缺省 http 状态码
+	DefaultCode int
+	// Domain 枚举的 domain
+	Domain string
+	Errors []*ErrorInfo
+	// Constructors 是否生成 NewXxx 构造函数, 对应插件参数 constructors
+	Constructors bool
+	// File 枚举所在文件
+	File *FileInfo
+	// Proto 枚举的 protogen 描述, 非 protobuf 来源时为 nil
+	Proto *protogen.Enum
+	// bizCodes 枚举内的业务错误码(含缺省的枚举值编号)及对应的错误, 由 Diagnostics.CheckError 记录
+	bizCodes map[int32]Site
+}
+
+// IsAlias 返回已添加的错误中是否有编号为 number 的错误, 即编号为 number 的错误是否为别名(allow_alias)
+func (w *ErrorWrapper) IsAlias(number int32) bool {
+	for _, e := range w.Errors {
+		if e.Number == number {
+			return true
+		}
+	}
+	return false
+}
+
+// Add 添加错误, 并按已添加的错误设置 Alias
+//
+//	只有生成的错误参与别名判断, 两个生成器共用以保证结果一致
+func (w *ErrorWrapper) Add(e *ErrorInfo) {
+	e.Alias = w.IsAlias(e.Number)
+	w.Errors = append(w.Errors, e)
+}
+
+// FileInfo 错误枚举所在文件的模板数据
+type FileInfo struct {
+	// Path proto 文件路径
+	Path string
+	// Package proto 包名
+	Package string
+	// GoPackageName 及 GoImportPath 生成代码所在的 Go 包
+	GoPackageName string
+	GoImportPath  string
+	// Proto 文件的 protogen 描述, 非 protobuf 来源时为 nil
+	Proto *protogen.File
+}
+
+// EntryTemplate 模板目录中作为入口的模板文件名
+const EntryTemplate = "errors.tmpl"
+
+// FuncMap 模板函数, 自定义模板可使用:
+//
+//	quote      字符串转为 Go 字符串字面量, 同 strconv.Quote
+//	camel      转为 UpperCamelCase
+//	lowerCamel 转为 lowerCamelCase
+//	snake      转为 snake_case
+//	upper/lower/hasPrefix/hasSuffix/trimPrefix/trimSuffix/replace/join 同 strings 中的函数
+//	qualify    qualify "importPath" "Name" 返回限定后的标识符并添加 import, 如 qualify "github.com/alkaid/goerrors/errors" "WithStack"
+func FuncMap(g *protogen.GeneratedFile) template.FuncMap {
+	return template.FuncMap{
+		"quote":      strconv.Quote,
+		"camel":      strcase.ToCamel,
+		"lowerCamel": strcase.ToLowerCamel,
+		"snake":      strcase.ToSnake,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"hasPrefix":  strings.HasPrefix,
+		"hasSuffix":  strings.HasSuffix,
+		"trimPrefix": strings.TrimPrefix,
+		"trimSuffix": strings.TrimSuffix,
+		"replace":    strings.ReplaceAll,
+		"join":       strings.Join,
+		"qualify": func(importPath, name string) string {
+			if g == nil {
+				return name
+			}
+			return g.QualifiedGoIdent(protogen.GoImportPath(importPath).Ident(name))
+		},
+	}
+}
+
+// LoadTemplate 加载 path 指定的模板文件或目录, path 为空时使用内置模板
+//
+//	目录中的所有 *.tmpl 文件一并解析, 以 errors.tmpl 为入口, 其他文件可通过 {{template "name.tmpl" .}} 引用
+func LoadTemplate(path string) (*template.Template, error) {
+	tmpl := template.New("errors").Funcs(FuncMap(nil))
+	if path == "" {
+		return tmpl.Parse(DefaultTemplate)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		t, err := tmpl.ParseFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		return t.Lookup(filepath.Base(path)), nil
+	}
+	t, err := tmpl.ParseGlob(filepath.Join(path, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	if t = t.Lookup(EntryTemplate); t == nil {
+		return nil, fmt.Errorf("template directory %s has no %s", path, EntryTemplate)
+	}
+	return t, nil
+}
+
+// Execute 以 w 为数据执行 tmpl, 模板中的 qualify 向 g 添加 import
+func (w *ErrorWrapper) Execute(tmpl *template.Template, g *protogen.GeneratedFile) (string, error) {
+	buf := new(bytes.Buffer)
+	t, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	if err := t.Funcs(FuncMap(g)).Execute(buf, w); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/alkaid/goerrors/cmd/internal/errorsgen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// site 返回 desc 的全名及在源文件中的位置
+func site(desc protoreflect.Descriptor) errorsgen.Site {
+	return errorsgen.Site{Name: string(desc.FullName()), Pos: position(desc)}
+}
+
+// position 返回 desc 在源文件中的位置, 格式为 file:line:column
+func position(desc protoreflect.Descriptor) string {
+	file := desc.ParentFile()
+	if file == nil {
+		return string(desc.FullName())
+	}
+	loc := file.SourceLocations().ByDescriptor(desc)
+	return fmt.Sprintf("%s:%d:%d", file.Path(), loc.StartLine+1, loc.StartColumn+1)
+}
+
+// lintNames 检查枚举名为 UpperCamelCase, 枚举值名为 UPPER_SNAKE_CASE
+func lintNames(d *errorsgen.Diagnostics, enum protoreflect.EnumDescriptor) {
+	d.LintEnumName(site(enum))
+	values := enum.Values()
+	for i := 0; i < values.Len(); i++ {
+		d.LintValueName(site(values.Get(i)))
+	}
+}
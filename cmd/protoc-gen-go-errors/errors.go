@@ -1,52 +1,48 @@
 package main
 
 import (
-	"fmt"
+	"strconv"
 	"strings"
+	"text/template"
 
+	"github.com/alkaid/goerrors/apierrors/http/status"
+	"github.com/alkaid/goerrors/cmd/internal/errorsgen"
 	"github.com/alkaid/goerrors/cmd/protoc-gen-go-errors/errors"
 
-	"github.com/iancoleman/strcase"
-
+	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
 )
 
 //go:generate protoc -I ./errors --go_out=paths=source_relative:./errors errors.proto
 
-const (
-	errorsPackage = protogen.GoImportPath("github.com/alkaid/goerrors/apierrors")
-)
+// errorsTmpl 生成使用的模板, 由插件参数 template 指定
+var errorsTmpl *template.Template
 
 // generateFile generates a _errors.pb.go file containing kratos errors definitions.
-func generateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
-	if len(file.Enums) == 0 {
+func generateFile(gen *protogen.Plugin, file *protogen.File, diags *errorsgen.Diagnostics) *protogen.GeneratedFile {
+	if len(allEnums(file)) == 0 {
 		return nil
 	}
-	filename := file.GeneratedFilenamePrefix + "_errors.pb.go"
+	filename := file.GeneratedFilenamePrefix + *suffix
 	g := gen.NewGeneratedFile(filename, file.GoImportPath)
-	g.P("// Code generated by protoc-gen-go-errors. DO NOT EDIT.")
-	g.P()
-	g.P("package ", file.GoPackageName)
-	g.P()
+	errorsgen.WriteHeader(g, "protoc-gen-go-errors", file.GoPackageName)
 	// it may need g.QualifiedGoIdent(fmtPackage.Ident(""))
-	generateFileContent(gen, file, g)
+	generateFileContent(gen, file, g, diags)
 	return g
 }
 
 // generateFileContent generates the kratos errors definitions, excluding the package statement.
-func generateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile) {
-	if len(file.Enums) == 0 {
+func generateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, diags *errorsgen.Diagnostics) {
+	enums := allEnums(file)
+	if len(enums) == 0 {
 		return
 	}
 
-	g.P("// This is a compile-time assertion to ensure that this generated file")
-	g.P("// is compatible with the kratos package it is being compiled against.")
-	g.P("const _ = ", errorsPackage.Ident("SupportPackageIsVersion1"))
-	g.P()
+	errorsgen.WriteAssertion(g)
 	index := 0
-	for _, enum := range file.Enums {
-		skip := genErrorsReason(gen, file, g, enum)
+	for _, enum := range enums {
+		skip := genErrorsReason(gen, file, g, enum, diags)
 		if !skip {
 			index++
 		}
@@ -57,18 +53,64 @@ func generateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.
 	}
 }
 
-func genErrorsReason(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, enum *protogen.Enum) bool {
+// allEnums 返回文件中的所有枚举, 包括嵌套在 message 中的枚举
+func allEnums(file *protogen.File) []*protogen.Enum {
+	enums := append([]*protogen.Enum(nil), file.Enums...)
+	var walk func(messages []*protogen.Message)
+	walk = func(messages []*protogen.Message) {
+		for _, m := range messages {
+			enums = append(enums, m.Enums...)
+			walk(m.Messages)
+		}
+	}
+	walk(file.Messages)
+	return enums
+}
+
+func genErrorsReason(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, enum *protogen.Enum, diags *errorsgen.Diagnostics) bool {
 	defaultCode := proto.GetExtension(enum.Desc.Options(), errors.E_DefaultCode)
 	code := 0
 	if ok := defaultCode.(int32); ok != 0 {
 		code = int(ok)
 	}
-	if code > 600 || code < 0 {
-		panic(fmt.Sprintf("Enum '%s' range must be greater than 0 and less than or equal to 600", string(enum.Desc.Name())))
+	// 未声明 default_code 时由 default_grpc_code 转换
+	defaultGRPCCode := proto.GetExtension(enum.Desc.Options(), errors.E_DefaultGrpcCode).(errors.RPCCode)
+	if code == 0 && defaultGRPCCode != errors.RPCCode_OK {
+		code = status.FromGRPCCode(codes.Code(defaultGRPCCode))
+	}
+	if !diags.CheckDefaultCode(site(enum.Desc), code) {
+		return true
+	}
+	// domain 优先取枚举选项, 未声明时取文件选项
+	domain := proto.GetExtension(enum.Desc.Options(), errors.E_Domain).(string)
+	if domain == "" {
+		domain = proto.GetExtension(file.Desc.Options(), errors.E_DefaultDomain).(string)
+	}
+	// 嵌套枚举的标识符以外层 message 限定, 规则见 errorsgen.Names
+	parent := strings.TrimSuffix(enum.GoIdent.GoName, "_"+string(enum.Desc.Name()))
+	if parent == enum.GoIdent.GoName {
+		parent = ""
+	}
+	ew := errorsgen.ErrorWrapper{
+		Enum:         enum.GoIdent.GoName,
+		EnumName:     string(enum.Desc.Name()),
+		EnumFullName: string(enum.Desc.FullName()),
+		EnumComment:  enum.Comments.Leading.String(),
+		DefaultCode:  code,
+		Domain:       domain,
+		Constructors: *constructors,
+		File:         newFileInfo(file),
+		Proto:        enum,
 	}
-	var ew errorWrapper
+	// 未声明错误码而被跳过的枚举值, 作为 lint 问题报告
+	var skipped []*protogen.EnumValue
 	for _, v := range enum.Values {
 		enumCode := code
+		grpcCode := defaultGRPCCode
+		if c := proto.GetExtension(v.Desc.Options(), errors.E_GrpcCode).(errors.RPCCode); c != errors.RPCCode_OK {
+			grpcCode = c
+			enumCode = status.FromGRPCCode(codes.Code(c))
+		}
 		eCode := proto.GetExtension(v.Desc.Options(), errors.E_Code)
 		if ok := eCode.(int32); ok != 0 {
 			enumCode = int(ok)
@@ -76,53 +118,96 @@ func genErrorsReason(gen *protogen.Plugin, file *protogen.File, g *protogen.Gene
 		desc := string(v.Desc.Name())
 		// If the current enumeration does not contain 'errors.code'
 		// or the code value exceeds the range, the current enum will be skipped
-		if enumCode > 600 || enumCode < 0 {
-			panic(fmt.Sprintf("Enum '%s' range must be greater than 0 and less than or equal to 600", string(v.Desc.Name())))
+		if !diags.CheckCode(site(v.Desc), enumCode) {
+			continue
 		}
 		if enumCode == 0 {
+			skipped = append(skipped, v)
 			continue
 		}
 		comment := v.Comments.Leading.String()
 		if comment == "" {
 			comment = v.Comments.Trailing.String()
 		}
-		upperCamelValue := strcase.ToCamel(desc)
-		comment = buildComment(upperCamelValue, comment)
+		upperCamelValue, lowerCamelValue := errorsgen.Names(parent, desc)
+		comment = errorsgen.Comment(upperCamelValue, comment)
 		pretty := ""
 		if proto.HasExtension(v.Desc.Options(), errors.E_Pretty) {
 			pretty = proto.GetExtension(v.Desc.Options(), errors.E_Pretty).(string)
 		}
-		msg := string(enum.Desc.Name()) + "_" + string(v.Desc.Name()) + ".String()"
+		msg := v.GoIdent.GoName + ".String()"
+		message := ""
 		if proto.HasExtension(v.Desc.Options(), errors.E_Message) {
-			msg = "\"" + proto.GetExtension(v.Desc.Options(), errors.E_Message).(string) + "\""
+			message = proto.GetExtension(v.Desc.Options(), errors.E_Message).(string)
+			msg = strconv.Quote(message)
+		}
+		severity := ""
+		if level := proto.GetExtension(v.Desc.Options(), errors.E_Severity).(errors.Level); level != errors.Level_LEVEL_UNSPECIFIED {
+			severity = "Level_" + level.String()
+		}
+		key := string(v.Desc.FullName())
+		reason := key
+		if *shortReason {
+			reason = desc
+		}
+		// 业务错误码缺省为枚举值编号
+		bizCode := int32(v.Desc.Number())
+		explicitBizCode := proto.HasExtension(v.Desc.Options(), errors.E_BizCode)
+		if explicitBizCode {
+			bizCode = proto.GetExtension(v.Desc.Options(), errors.E_BizCode).(int32)
 		}
-		err := &errorInfo{
+		grpcName := ""
+		if grpcCode != errors.RPCCode_OK {
+			grpcName = codes.Code(grpcCode).String()
+		}
+		err := &errorsgen.ErrorInfo{
 			Name:            string(enum.Desc.Name()),
 			Value:           string(v.Desc.Name()),
 			HTTPCode:        enumCode,
 			UpperCamelValue: upperCamelValue,
-			LowerCamelValue: strcase.ToLowerCamel(desc),
-			Key:             string(v.Desc.FullName()),
+			LowerCamelValue: lowerCamelValue,
+			Key:             key,
+			Reason:          reason,
+			Domain:          domain,
 			Comment:         comment,
 			HasComment:      len(comment) > 0,
 			Pretty:          pretty,
 			Msg:             msg,
+			Severity:        severity,
+			Expected:        proto.GetExtension(v.Desc.Options(), errors.E_Expected).(bool),
+			BizCode:         bizCode,
+			GRPCCode:        grpcName,
+			EnumValue:       v.GoIdent.GoName,
+			Message:         message,
+			Number:          int32(v.Desc.Number()),
+			Proto:           v,
 		}
-		ew.Errors = append(ew.Errors, err)
+		diags.CheckError(&ew, err, site(v.Desc), explicitBizCode)
+		ew.Add(err)
 	}
 	if len(ew.Errors) == 0 {
 		return true
 	}
-	g.P(ew.execute())
+	lintNames(diags, enum.Desc)
+	for _, v := range skipped {
+		diags.Skipped(site(v.Desc))
+	}
+	content, err := ew.Execute(errorsTmpl, g)
+	if err != nil {
+		diags.Add(site(enum.Desc), "execute template: %v", err)
+		return true
+	}
+	g.P(content)
 	return false
 }
 
-// buildComment returns comment content with prefix //
-func buildComment(upperCamelValue, comment string) string {
-	if comment == "" {
-		return ""
+// newFileInfo 返回 file 的模板数据
+func newFileInfo(file *protogen.File) *errorsgen.FileInfo {
+	return &errorsgen.FileInfo{
+		Path:          file.Desc.Path(),
+		Package:       string(file.Desc.Package()),
+		GoPackageName: string(file.GoPackageName),
+		GoImportPath:  string(file.GoImportPath),
+		Proto:         file,
 	}
-
-	comment = strings.Replace(comment, "//", "", 1)
-	return fmt.Sprintf("// %s %s", upperCamelValue, comment)
 }
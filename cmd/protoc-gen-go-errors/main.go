@@ -3,7 +3,10 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
+	"os"
 
+	"github.com/alkaid/goerrors/cmd/internal/errorsgen"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/types/pluginpb"
 )
@@ -12,25 +15,46 @@ var showVersion = flag.Bool("version", false, "print the version and exit")
 
 var version string
 
+// plugin parameters, e.g. --go-errors_out=suffix=_apierrors.pb.go,short_reason=true,paths=source_relative:.
+var (
+	flags        flag.FlagSet
+	suffix       = flags.String("suffix", "_errors.pb.go", "suffix of the generated file name")
+	shortReason  = flags.Bool("short_reason", false, "use the enum value name instead of the full proto name as reason, see AIP-193")
+	tmplPath     = flags.String("template", "", "custom template file, or directory containing errors.tmpl")
+	constructors = flags.Bool("constructors", false, "also generate NewXxx(cause, format, args...) constructors returning errors with stack")
+	strict       = flags.Bool("strict", false, "fail instead of warn on skipped values, duplicate reasons, missing pretty for 4xx, quoted messages and naming violations")
+)
+
 func main() {
 	flag.Parse()
 	if *showVersion {
 		fmt.Printf("Version: %s\n", version)
 		return
 	}
-	var flags flag.FlagSet
 	protogen.Options{
 		ParamFunc: flags.Set,
 	}.Run(func(gen *protogen.Plugin) error {
-		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
-		for _, f := range gen.Files {
-			if !f.Generate {
-				continue
-			}
-			generateFile(gen, f)
-		}
-		return nil
+		return run(gen, os.Stderr)
 	})
 }
 
+// run 生成 gen 中需要生成的文件, 非 strict 模式下的 lint 问题作为警告输出到 w
+func run(gen *protogen.Plugin, w io.Writer) error {
+	gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	tmpl, err := errorsgen.LoadTemplate(*tmplPath)
+	if err != nil {
+		return err
+	}
+	errorsTmpl = tmpl
+	diags := &errorsgen.Diagnostics{Strict: *strict}
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		generateFile(gen, f, diags)
+	}
+	diags.Warn(w)
+	return diags.Err()
+}
+
 //go:generate protoc --proto_path=. --go_out=paths=source_relative:. --go-errors_out=paths=source_relative:. test/test.proto
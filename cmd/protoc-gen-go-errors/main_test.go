@@ -0,0 +1,499 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// request 编译 src 为 a.proto, 返回与 protoc 相同的 CodeGeneratorRequest
+func request(t *testing.T, src, param string) *pluginpb.CodeGeneratorRequest {
+	t.Helper()
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: func(path string) (io.ReadCloser, error) {
+				if path == "a.proto" {
+					return io.NopCloser(strings.NewReader(src)), nil
+				}
+				return os.Open(path)
+			},
+		}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+	files, err := compiler.Compile(context.Background(), "a.proto")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"a.proto"},
+		Parameter:      proto.String(param),
+	}
+	seen := map[string]bool{}
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		for i := 0; i < fd.Imports().Len(); i++ {
+			add(fd.Imports().Get(i).FileDescriptor)
+		}
+		req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(fd))
+	}
+	add(files[0])
+	// 同 protoc 一样经序列化传入, 选项中的扩展按已注册的 errors 扩展解析
+	b, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = &pluginpb.CodeGeneratorRequest{}
+	if err := proto.Unmarshal(b, req); err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// generate 以插件参数 param 生成 src, 返回插件响应及警告
+func generate(t *testing.T, src, param string) (*pluginpb.CodeGeneratorResponse, string) {
+	t.Helper()
+	t.Cleanup(func() {
+		for _, name := range []string{"strict", "short_reason", "constructors"} {
+			_ = flags.Set(name, "false")
+		}
+		_ = flags.Set("template", "")
+	})
+	gen, err := protogen.Options{ParamFunc: flags.Set}.New(request(t, src, param))
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings := &bytes.Buffer{}
+	if err := run(gen, warnings); err != nil {
+		gen.Error(err)
+	}
+	return gen.Response(), warnings.String()
+}
+
+// pos 返回 src 中首个包含 s 的行的位置, 格式同 position
+func pos(src, s string) string {
+	for i, line := range strings.Split(src, "\n") {
+		if col := strings.Index(line, s); col >= 0 {
+			return fmt.Sprintf("a.proto:%d:%d", i+1, col+1)
+		}
+	}
+	panic("not found: " + s)
+}
+
+const lintProto = `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum ErrorReason {
+  option (errors.default_code) = 500;
+  NOT_FOUND = 0 [(errors.code) = 404];
+  bad_name = 1 [(errors.code) = 500];
+}
+
+enum Mixed {
+  MIXED_UNSPECIFIED = 0;
+  MIXED_CONFLICT = 2 [(errors.code) = 409, (errors.pretty) = "conflict"];
+}
+`
+
+func TestDiagnostics(t *testing.T) {
+	wantLint := []string{
+		pos(lintProto, "NOT_FOUND") + ": %senum value 'a.NOT_FOUND' with code 404 should declare pretty",
+		pos(lintProto, "bad_name") + ": %senum value 'a.bad_name' name should be UPPER_SNAKE_CASE",
+		pos(lintProto, "MIXED_UNSPECIFIED") + ": %senum value 'a.MIXED_UNSPECIFIED' is skipped because it declares no code",
+	}
+	t.Run("non-strict warns", func(t *testing.T) {
+		resp, warnings := generate(t, lintProto, "strict=false")
+		if resp.GetError() != "" {
+			t.Fatalf("unexpected plugin error: %s", resp.GetError())
+		}
+		if len(resp.File) != 1 {
+			t.Fatalf("got %d files, want 1", len(resp.File))
+		}
+		for _, w := range wantLint {
+			if w = fmt.Sprintf(w, "warning: "); !strings.Contains(warnings, w) {
+				t.Errorf("warnings missing %q, got:\n%s", w, warnings)
+			}
+		}
+	})
+	t.Run("strict fails", func(t *testing.T) {
+		resp, warnings := generate(t, lintProto, "strict=true")
+		if warnings != "" {
+			t.Errorf("unexpected warnings in strict mode: %s", warnings)
+		}
+		for _, w := range wantLint {
+			if w = fmt.Sprintf(w, ""); !strings.Contains(resp.GetError(), w) {
+				t.Errorf("plugin error missing %q, got:\n%s", w, resp.GetError())
+			}
+		}
+	})
+}
+
+func TestDiagnosticsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want func(src string) string
+	}{
+		{
+			name: "code out of range",
+			src: `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum ErrorReason {
+  TOO_BIG = 0 [(errors.code) = 700];
+}
+`,
+			want: func(src string) string {
+				return pos(src, "TOO_BIG") + ": enum value 'a.TOO_BIG' code 700 must be greater than 0 and less than or equal to 600"
+			},
+		},
+		{
+			name: "duplicate biz_code",
+			src: `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum ErrorReason {
+  option (errors.default_code) = 400;
+  FIRST = 0 [(errors.biz_code) = 40001];
+  SECOND = 1 [(errors.biz_code) = 40001];
+}
+`,
+			want: func(src string) string {
+				return pos(src, "SECOND") + ": enum value 'a.SECOND' biz_code 40001 is already used by 'a.FIRST' at " + pos(src, "FIRST")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := generate(t, tt.src, "")
+			if want := tt.want(tt.src); !strings.Contains(resp.GetError(), want) {
+				t.Errorf("plugin error missing %q, got:\n%s", want, resp.GetError())
+			}
+		})
+	}
+}
+
+func TestAllowAlias(t *testing.T) {
+	src := `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum ErrorReason {
+  option allow_alias = true;
+  option (errors.default_code) = 404;
+  UNSPECIFIED = 0 [(errors.code) = 500];
+  NOT_FOUND = 1 [(errors.pretty) = "not found"];
+  MISSING = 1 [(errors.pretty) = "missing"];
+}
+`
+	resp, _ := generate(t, src, "strict=true")
+	if resp.GetError() != "" {
+		t.Fatalf("unexpected plugin error: %s", resp.GetError())
+	}
+	content := resp.File[0].GetContent()
+	if !strings.Contains(content, "case ErrorReason_NOT_FOUND:") || strings.Contains(content, "case ErrorReason_MISSING:") {
+		t.Errorf("Error() should switch on the first of aliased values:\n%s", content)
+	}
+}
+
+func TestEnumOfMatchesOuterError(t *testing.T) {
+	src := `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum ErrorReason {
+  option (errors.default_code) = 400;
+  STATE_MISMATCH = 0;
+  CONTENT_MISSING = 1;
+}
+`
+	resp, _ := generate(t, src, "")
+	if resp.GetError() != "" {
+		t.Fatalf("unexpected plugin error: %s", resp.GetError())
+	}
+	content := resp.File[0].GetContent()
+	want := "case se.Reason == sTATEMISMATCH.Reason && se.Domain == sTATEMISMATCH.Domain:"
+	if !strings.Contains(content, want) || strings.Contains(content, "apierrors.Is(se,") {
+		t.Errorf("ErrorReasonOf should compare the outer error only, want %q in:\n%s", want, content)
+	}
+}
+
+func TestBizCodeAcrossEnums(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		// want 插件错误应包含的内容, 为空时应生成成功
+		want func(src string) string
+	}{
+		{
+			name: "default biz_code in several enums",
+			src: `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum UserReason {
+  option (errors.default_code) = 404;
+  USER_UNSPECIFIED = 0;
+  USER_NOT_FOUND = 1;
+}
+
+enum OrderReason {
+  option (errors.default_code) = 404;
+  ORDER_UNSPECIFIED = 0;
+  ORDER_NOT_FOUND = 1;
+}
+`,
+		},
+		{
+			name: "explicit biz_code in several enums",
+			src: `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum UserReason {
+  option (errors.default_code) = 404;
+  USER_NOT_FOUND = 0 [(errors.biz_code) = 40401];
+}
+
+enum OrderReason {
+  option (errors.default_code) = 404;
+  ORDER_NOT_FOUND = 0 [(errors.biz_code) = 40401];
+}
+`,
+			want: func(src string) string {
+				return pos(src, "ORDER_NOT_FOUND") + ": enum value 'a.ORDER_NOT_FOUND' biz_code 40401 is already used by 'a.USER_NOT_FOUND' at " + pos(src, "USER_NOT_FOUND")
+			},
+		},
+		{
+			name: "explicit biz_code equals default in the same enum",
+			src: `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum ErrorReason {
+  option (errors.default_code) = 404;
+  UNSPECIFIED = 0;
+  NOT_FOUND = 1;
+  MISSING = 2 [(errors.biz_code) = 1];
+}
+`,
+			want: func(src string) string {
+				return pos(src, "MISSING") + ": enum value 'a.MISSING' biz_code 1 is already used by 'a.NOT_FOUND' at " + pos(src, "NOT_FOUND")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := generate(t, tt.src, "")
+			if tt.want == nil {
+				if resp.GetError() != "" {
+					t.Fatalf("unexpected plugin error: %s", resp.GetError())
+				}
+				return
+			}
+			if want := tt.want(tt.src); !strings.Contains(resp.GetError(), want) {
+				t.Errorf("plugin error missing %q, got:\n%s", want, resp.GetError())
+			}
+		})
+	}
+}
+
+func TestNestedEnum(t *testing.T) {
+	src := `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+message UserService {
+  enum Reason {
+    option (errors.default_code) = 404;
+    NOT_FOUND = 0;
+  }
+}
+`
+	resp, _ := generate(t, src, "")
+	if resp.GetError() != "" {
+		t.Fatalf("unexpected plugin error: %s", resp.GetError())
+	}
+	content := resp.File[0].GetContent()
+	// 标识符以外层 message 限定, 枚举值名按 errorsgen.Names 转换, reason 仍为枚举值全名
+	for _, want := range []string{
+		"var userService_NOTFOUND *apierrors.Error",
+		`apierrors.New(404, "a.UserService.NOT_FOUND", UserService_NOT_FOUND.String(), "")`,
+		"func UserService_NOTFOUND() *apierrors.Error {",
+		"func UserService_ReasonOf(err error) (UserService_Reason, bool) {",
+		"func (x UserService_Reason) Error() *apierrors.Error {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+}
+
+func TestGRPCCode(t *testing.T) {
+	src := `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum ErrorReason {
+  option (errors.default_code) = 500;
+  UNSPECIFIED = 0;
+  ORDER_PAID = 1 [(errors.grpc_code) = FAILED_PRECONDITION];
+  ORDER_EXISTS = 2 [(errors.grpc_code) = ALREADY_EXISTS, (errors.code) = 422];
+}
+
+enum Timeout {
+  option (errors.default_grpc_code) = DEADLINE_EXCEEDED;
+  TIMEOUT_UNSPECIFIED = 0;
+}
+`
+	resp, _ := generate(t, src, "")
+	if resp.GetError() != "" {
+		t.Fatalf("unexpected plugin error: %s", resp.GetError())
+	}
+	content := resp.File[0].GetContent()
+	for _, want := range []string{
+		// 未声明 grpc_code 时不设置, 由 code 转换
+		`uNSPECIFIED = apierrors.New(500, "a.UNSPECIFIED", ErrorReason_UNSPECIFIED.String(), "")` + "\n",
+		// code 由 grpc_code 转换
+		`apierrors.New(400, "a.ORDER_PAID", ErrorReason_ORDER_PAID.String(), "").WithBizCode(1).WithGRPCCode(codes.FailedPrecondition)`,
+		// 显式声明的 code 优先
+		`apierrors.New(422, "a.ORDER_EXISTS", ErrorReason_ORDER_EXISTS.String(), "").WithBizCode(2).WithGRPCCode(codes.AlreadyExists)`,
+		`apierrors.New(504, "a.TIMEOUT_UNSPECIFIED", Timeout_TIMEOUT_UNSPECIFIED.String(), "").WithGRPCCode(codes.DeadlineExceeded)`,
+		`codes "google.golang.org/grpc/codes"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+}
+
+func TestCustomTemplate(t *testing.T) {
+	src := `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum ErrorReason {
+  option (errors.default_code) = 404;
+  USER_NOT_FOUND = 0 [(errors.pretty) = "用户不存在"];
+}
+`
+	// 入口模板引用同目录的其他模板, 并使用 FuncMap 中的函数
+	dir := t.TempDir()
+	entry := `{{range .Errors}}// {{camel (lower .Value)}} {{snake .Value}} {{.HTTPCode}}
+var {{lowerCamel (lower .Value)}}Pretty = {{quote .Pretty}}
+{{template "stack.tmpl" .}}
+{{end}}`
+	stack := `func {{camel (lower .Value)}}WithStack(err error) error {
+	return {{qualify "github.com/alkaid/goerrors/errors" "WithStack"}}(err)
+}
+`
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write("errors.tmpl", entry)
+	write("stack.tmpl", stack)
+	file := write("single.tmpl", `var enum{{.Enum}} = {{quote .EnumFullName}}
+`)
+	tests := []struct {
+		name  string
+		param string
+		want  []string
+	}{
+		{
+			name:  "directory",
+			param: "template=" + dir,
+			want: []string{
+				"// UserNotFound user_not_found 404",
+				`var userNotFoundPretty = "用户不存在"`,
+				"func UserNotFoundWithStack(err error) error {",
+				"return errors.WithStack(err)",
+				`errors "github.com/alkaid/goerrors/errors"`,
+			},
+		},
+		{
+			name:  "file",
+			param: "template=" + file,
+			want:  []string{`var enumErrorReason = "a.ErrorReason"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := generate(t, src, tt.param)
+			if resp.GetError() != "" {
+				t.Fatalf("unexpected plugin error: %s", resp.GetError())
+			}
+			content := resp.File[0].GetContent()
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("missing %q in:\n%s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomTemplateErrors(t *testing.T) {
+	src := `syntax = "proto3";
+package a;
+import "errors/errors.proto";
+option go_package = "example.com/a;a";
+
+enum ErrorReason {
+  option (errors.default_code) = 404;
+  USER_NOT_FOUND = 0;
+}
+`
+	noEntry := t.TempDir()
+	if err := os.WriteFile(filepath.Join(noEntry, "other.tmpl"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "missing", path: filepath.Join(t.TempDir(), "missing.tmpl"), want: "missing.tmpl"},
+		{name: "no entry", path: noEntry, want: "has no errors.tmpl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := generate(t, src, "template="+tt.path)
+			if !strings.Contains(resp.GetError(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", resp.GetError(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// stackDepth 记录的最大堆栈深度, 与 github.com/pkg/errors 一致
+const stackDepth = 32
+
+// withStack 与 github.com/pkg/errors 的 withStack 行为一致, 可跳过指定层数的调用者
+type withStack struct {
+	error
+	stack []uintptr
+}
+
+// WithStackSkip 同 WithStack, 但堆栈从调用者向上跳过 skip 层开始记录
+//
+//	用于封装了 WithStack 的辅助函数, 使堆栈从辅助函数的调用处开始. skip 为 0 时与 WithStack 相同.
+//	返回的错误实现 StackTrace() errors.StackTrace, 与 github.com/pkg/errors 兼容
+func WithStackSkip(err error, skip int) error {
+	if err == nil {
+		return nil
+	}
+	pcs := make([]uintptr, stackDepth)
+	n := runtime.Callers(2+skip, pcs) //nolint:gomnd // 跳过 runtime.Callers 及 WithStackSkip
+	return &withStack{error: err, stack: pcs[:n]}
+}
+
+// StackTrace returns the stack trace, compatible with github.com/pkg/errors.
+func (w *withStack) StackTrace() errors.StackTrace {
+	f := make([]errors.Frame, len(w.stack))
+	for i, pc := range w.stack {
+		f[i] = errors.Frame(pc)
+	}
+	return f
+}
+
+// Cause returns the underlying cause of the error.
+func (w *withStack) Cause() error { return w.error }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withStack) Unwrap() error { return w.error }
+
+// Format 与 github.com/pkg/errors 一致, %+v 时输出堆栈
+func (w *withStack) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v", w.Cause())
+			w.StackTrace().Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// topFrame 返回 err 记录的堆栈中最内层帧的函数名
+func topFrame(t *testing.T, err error) string {
+	t.Helper()
+	var st interface{ StackTrace() errors.StackTrace }
+	if !As(err, &st) {
+		t.Fatalf("%v has no stack trace", err)
+	}
+	frames := st.StackTrace()
+	if len(frames) == 0 {
+		t.Fatalf("%v has an empty stack trace", err)
+	}
+	return runtime.FuncForPC(uintptr(frames[0]) - 1).Name()
+}
+
+// wrapSkip 模拟封装了 WithStackSkip 的辅助函数
+func wrapSkip(err error, skip int) error {
+	return WithStackSkip(err, skip)
+}
+
+func TestWithStackSkip(t *testing.T) {
+	err := New("boom")
+	tests := []struct {
+		name string
+		skip int
+		want string
+	}{
+		// skip 为 0 时与 WithStack 相同, 从辅助函数开始记录
+		{name: "helper", skip: 0, want: ".wrapSkip"},
+		// skip 为 1 时从辅助函数的调用处(子测试的闭包)开始记录
+		{name: "caller", skip: 1, want: ".TestWithStackSkip.func1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := topFrame(t, wrapSkip(err, tt.skip))
+			if !strings.HasSuffix(got, tt.want) {
+				t.Errorf("top frame = %s, want suffix %s", got, tt.want)
+			}
+		})
+	}
+	if WithStackSkip(nil, 0) != nil {
+		t.Errorf("WithStackSkip(nil) should be nil")
+	}
+}
+
+func TestWithStackSkipFormat(t *testing.T) {
+	err := wrapSkip(New("boom"), 1)
+	if got := fmt.Sprintf("%v", err); got != "boom" {
+		t.Errorf("%%v = %q, want boom", got)
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, "boom\n") || !strings.Contains(got, "TestWithStackSkipFormat") {
+		t.Errorf("%%+v should print the message and the stack starting at the caller:\n%s", got)
+	}
+	if !Is(err, Unwrap(err)) || Cause(err).Error() != "boom" {
+		t.Errorf("the wrapped error should be reachable through Unwrap and Cause")
+	}
+}